@@ -0,0 +1,125 @@
+package indeed
+
+import (
+	"strings"
+	"unicode"
+)
+
+type EventAction string
+
+// Event actions registered for RDAP (RFC 9083, section 10.2.3).
+const (
+	ActionRegistration             EventAction = "registration"
+	ActionReregistration           EventAction = "reregistration"
+	ActionLastChanged              EventAction = "last changed"
+	ActionExpiration               EventAction = "expiration"
+	ActionDeletion                 EventAction = "deletion"
+	ActionReinstantiation          EventAction = "reinstantiation"
+	ActionTransfer                 EventAction = "transfer"
+	ActionLocked                   EventAction = "locked"
+	ActionUnlocked                 EventAction = "unlocked"
+	ActionLastUpdate               EventAction = "last update of RDAP database"
+	ActionRegistrarExpiration      EventAction = "registrar expiration"
+	ActionEnumValidationExpiration EventAction = "enum validation expiration"
+)
+
+// ActionLastWHOISUpdate is the WHOIS counterpart of ActionLastUpdate.
+const ActionLastWHOISUpdate EventAction = "last update of WHOIS database"
+
+// Event actions for changes detected between snapshots.
+const (
+	ActionStatusAdded        EventAction = "status added"
+	ActionStatusRemoved      EventAction = "status removed"
+	ActionNameserversChanged EventAction = "nameservers changed"
+	ActionRegistrarChanged   EventAction = "registrar changed"
+	ActionDelegationSigned   EventAction = "delegation signed"
+	ActionDelegationUnsigned EventAction = "delegation unsigned"
+	ActionDSChanged          EventAction = "DS records changed"
+	ActionSearchMatched      EventAction = "search matched"
+	ActionRemarkAdded        EventAction = "remark added"
+	ActionRemarkRemoved      EventAction = "remark removed"
+)
+
+// ActionOther stands in for actions outside the registry; the original
+// value is kept in Event.Detail.
+const ActionOther EventAction = "other"
+
+var eventActions = map[EventAction]bool{
+	ActionRegistration:             true,
+	ActionReregistration:           true,
+	ActionLastChanged:              true,
+	ActionExpiration:               true,
+	ActionDeletion:                 true,
+	ActionReinstantiation:          true,
+	ActionTransfer:                 true,
+	ActionLocked:                   true,
+	ActionUnlocked:                 true,
+	ActionLastUpdate:               true,
+	ActionLastWHOISUpdate:          true,
+	ActionRegistrarExpiration:      true,
+	ActionEnumValidationExpiration: true,
+	ActionStatusAdded:              true,
+	ActionStatusRemoved:            true,
+	ActionNameserversChanged:       true,
+	ActionRegistrarChanged:         true,
+	ActionDelegationSigned:         true,
+	ActionDelegationUnsigned:       true,
+	ActionDSChanged:                true,
+	ActionSearchMatched:            true,
+	ActionRemarkAdded:              true,
+	ActionRemarkRemoved:            true,
+}
+
+var eventActionAliases = map[string]EventAction{
+	"creation":                          ActionRegistration,
+	"created":                           ActionRegistration,
+	"updated":                           ActionLastChanged,
+	"last updated":                      ActionLastChanged,
+	"expiry":                            ActionExpiration,
+	"registry expiration":               ActionExpiration,
+	"registry expiry":                   ActionExpiration,
+	"registrar expiry":                  ActionRegistrarExpiration,
+	"registrar registration expiration": ActionRegistrarExpiration,
+	"enum verification expiration":      ActionEnumValidationExpiration,
+	"transferred":                       ActionTransfer,
+	"deleted":                           ActionDeletion,
+}
+
+// ParseEventAction maps an event action as written by RDAP or WHOIS
+// servers to a registered action. Case, separators and camel case are
+// ignored. Unknown actions map to ActionOther.
+func ParseEventAction(s string) EventAction {
+	key := normalizeAction(s)
+	if action, ok := eventActionAliases[key]; ok {
+		return action
+	}
+	for action := range eventActions {
+		if strings.ToLower(string(action)) == key {
+			return action
+		}
+	}
+	return ActionOther
+}
+
+func (a EventAction) Known() bool {
+	return eventActions[a]
+}
+
+func normalizeAction(s string) string {
+	var b strings.Builder
+	prev := ' '
+	for _, r := range strings.TrimSpace(s) {
+		switch {
+		case r == '_' || r == '-' || unicode.IsSpace(r):
+			r = ' '
+		case unicode.IsUpper(r) && unicode.IsLower(prev):
+			b.WriteByte(' ')
+		}
+		if r == ' ' && prev == ' ' {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+		prev = r
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,33 @@
+package indeed
+
+import "testing"
+
+func TestParseEventAction(t *testing.T) {
+	tests := []struct {
+		s    string
+		want EventAction
+	}{
+		{"registration", ActionRegistration},
+		{"Last Changed", ActionLastChanged},
+		{"lastChanged", ActionLastChanged},
+		{"registrar_expiration", ActionRegistrarExpiration},
+		{"last update of RDAP database", ActionLastUpdate},
+		{"Last update of WHOIS database", ActionLastWHOISUpdate},
+		{"enum validation expiration", ActionEnumValidationExpiration},
+		{"Creation", ActionRegistration},
+		{"DS records changed", ActionDSChanged},
+		{"auction closed", ActionOther},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.s, func(t *testing.T) {
+			got := ParseEventAction(tc.s)
+			if got != tc.want {
+				t.Fatalf("got: %q; want: %q", got, tc.want)
+			}
+			if got.Known() != (tc.want != ActionOther) {
+				t.Fatalf("got known: %t; want: %t", got.Known(), tc.want != ActionOther)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package indeed
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const IANABootstrapURL = "https://data.iana.org/rdap/dns.json"
+
+// bootstrapSample is a trimmed copy of the IANA registry covering a few
+// top-level domains, used when the full registry cannot be loaded.
+//
+//go:embed bootstrap/dns-sample.json
+var bootstrapSample []byte
+
+type Bootstrap struct {
+	services map[string][]string
+}
+
+// DefaultBootstrap returns the embedded sample bootstrap. Other top-level
+// domains are looked up at BaseURL; load IANABootstrapURL for all of them.
+func DefaultBootstrap() *Bootstrap {
+	b, err := ParseBootstrap(bytes.NewReader(bootstrapSample))
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func LoadBootstrap(ctx context.Context, src string) (*Bootstrap, error) {
+	return loadBootstrap(ctx, http.DefaultClient, src)
+}
+
+func loadBootstrap(ctx context.Context, client *http.Client, src string) (*Bootstrap, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		f, err := os.Open(src)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		return ParseBootstrap(f)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %q", res.Status)
+	}
+
+	return ParseBootstrap(res.Body)
+}
+
+func ParseBootstrap(r io.Reader) (*Bootstrap, error) {
+	var body struct {
+		Services [][][]string `json:"services"`
+	}
+	if err := json.NewDecoder(r).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	b := &Bootstrap{
+		services: make(map[string][]string),
+	}
+
+	for _, service := range body.Services {
+		if len(service) != 2 {
+			return nil, fmt.Errorf("malformed bootstrap service %q", service)
+		}
+		for _, tld := range service[0] {
+			b.services[strings.ToLower(tld)] = service[1]
+		}
+	}
+
+	return b, nil
+}
+
+func (b *Bootstrap) Lookup(name string) (string, bool) {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+
+	for {
+		if urls, ok := b.services[name]; ok && len(urls) > 0 {
+			for _, url := range urls {
+				if strings.HasPrefix(url, "https://") {
+					return url, true
+				}
+			}
+			return urls[0], true
+		}
+
+		_, after, found := strings.Cut(name, ".")
+		if !found {
+			return "", false
+		}
+		name = after
+	}
+}
@@ -0,0 +1,88 @@
+package indeed
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBootstrapLookup(t *testing.T) {
+	b, err := ParseBootstrap(strings.NewReader(`{
+		"services": [
+			[["com"], ["http://rdap.example/com/", "https://rdap.example/com/"]],
+			[["co.uk"], ["https://rdap.example/co.uk/"]],
+			[["uk"], ["https://rdap.example/uk/"]]
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"example.com", "https://rdap.example/com/", true},
+		{"EXAMPLE.COM.", "https://rdap.example/com/", true},
+		{"www.example.co.uk", "https://rdap.example/co.uk/", true},
+		{"example.uk", "https://rdap.example/uk/", true},
+		{"example.org", "", false},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := b.Lookup(tc.name)
+			if got != tc.want || ok != tc.ok {
+				t.Fatalf("got: %q, %t; want: %q, %t", got, ok, tc.want, tc.ok)
+			}
+		})
+	}
+}
+
+func TestDefaultBootstrap(t *testing.T) {
+	got, ok := DefaultBootstrap().Lookup("example.com")
+	if want := "https://rdap.verisign.com/com/v1/"; got != want || !ok {
+		t.Fatalf("got: %q, %t; want: %q, %t", got, ok, want, true)
+	}
+}
+
+func TestRDAPBootstrap(t *testing.T) {
+	registry := httptest.NewServer(http.HandlerFunc(rdapHandler))
+	defer registry.Close()
+
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/domain/example.org" {
+			http.Error(w, "unexpected fallback", http.StatusInternalServerError)
+			return
+		}
+		rdapHandler(w, r)
+	}))
+	defer fallback.Close()
+
+	iana := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"services": [[["com", "net"], [%q]]]}`, registry.URL+"/")
+	}))
+	defer iana.Close()
+
+	b, err := LoadBootstrap(context.Background(), iana.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewRDAPClient(fallback.URL)
+	client.Bootstrap = b
+
+	for _, name := range []string{"example.com", "example.net", "example.org"} {
+		domain, err := client.Resolve(context.Background(), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if domain == nil || !strings.EqualFold(domain.Name, name) {
+			t.Fatalf("got: %v; want: %s", domain, name)
+		}
+	}
+}
@@ -1,16 +1,75 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"flag"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/axeljohnsson/indeed"
+	"golang.org/x/text/encoding/htmlindex"
 )
 
-var addr = flag.String("addr", ":8080", "HTTP network address")
+var (
+	addr       = flag.String("addr", ":8080", "HTTP network address")
+	bootstrap  = flag.String("bootstrap", indeed.IANABootstrapURL, "RDAP bootstrap file or URL, falling back to an embedded sample of a few top-level domains")
+	link       = flag.String("link", "", "feed item link template with a {name} placeholder (default RDAP self link)")
+	referrals  = flag.Int("referrals", 0, "maximum depth of RDAP referrals to follow")
+	rate       = flag.Float64("rate", 0, "maximum RDAP requests per second and server (0 for unlimited)")
+	retries    = flag.Int("retries", 2, "maximum RDAP retries after a 429 or 503 response")
+	timeout    = flag.Duration("timeout", 30*time.Second, "RDAP request timeout")
+	userAgent  = flag.String("user-agent", "indeed (+https://github.com/axeljohnsson/indeed)", "RDAP User-Agent header")
+	proxy      = flag.String("proxy", "", "RDAP proxy URL (default from environment)")
+	caFile     = flag.String("ca-file", "", "PEM file of CA certificates to trust for RDAP")
+	certFile   = flag.String("cert-file", "", "PEM file of the RDAP client certificate")
+	keyFile    = flag.String("key-file", "", "PEM file of the RDAP client key")
+	ianaWHOIS  = flag.String("iana-whois", indeed.IANAWHOISServer, "WHOIS server to ask for the WHOIS server of a top-level domain")
+	whoisRefs  = flag.Int("whois-referrals", 1, "maximum depth of registrar WHOIS referrals to follow")
+	whoisDial  = flag.Duration("whois-dial-timeout", 10*time.Second, "WHOIS connect timeout")
+	whoisRead  = flag.Duration("whois-read-timeout", 30*time.Second, "WHOIS response timeout")
+	whoisRetry = flag.Int("whois-retries", 2, "maximum WHOIS retries after a rate limit message")
+	whoisConf  = flag.String("whois-config", "", "JSON file mapping top-level domains to WHOIS server, query template, profile and charset")
+	bearer     = make(map[string]string)
+	servers    = make(map[string]string)
+	charsets   = make(map[string]string)
+)
+
+func init() {
+	flag.Func("bearer", "RDAP bearer token for a server as host=token (repeatable)", func(value string) error {
+		host, token, found := strings.Cut(value, "=")
+		if !found || host == "" || token == "" {
+			return fmt.Errorf("want host=token")
+		}
+		bearer[host] = token
+		return nil
+	})
+	flag.Func("whois-server", "WHOIS server for a top-level domain as tld=host[:port], - for none (repeatable)", func(value string) error {
+		tld, server, found := strings.Cut(value, "=")
+		if !found || tld == "" || server == "" {
+			return fmt.Errorf("want tld=host[:port]")
+		}
+		servers[strings.ToLower(strings.TrimPrefix(tld, "."))] = server
+		return nil
+	})
+	flag.Func("whois-charset", "charset of a WHOIS server's responses as host=charset (repeatable)", func(value string) error {
+		host, charset, found := strings.Cut(value, "=")
+		if !found || host == "" || charset == "" {
+			return fmt.Errorf("want host=charset")
+		}
+		if _, err := htmlindex.Get(charset); err != nil {
+			return fmt.Errorf("charset %q: %w", charset, err)
+		}
+		charsets[strings.ToLower(host)] = charset
+		return nil
+	})
+}
 
 func main() {
 	if err := mainErr(); err != nil {
@@ -22,10 +81,100 @@ func main() {
 func mainErr() error {
 	flag.Parse()
 
-	rdap := indeed.NewRDAPClient(indeed.RDAPBaseURL)
+	opts, err := rdapOptions()
+	if err != nil {
+		return err
+	}
+
+	rdap := indeed.NewRDAPClient(indeed.RDAPBaseURL, opts...)
+	rdap.Bootstrap = indeed.DefaultBootstrap()
+	rdap.Referrals = *referrals
+	rdap.LinkTemplate = *link
+	rdap.DefaultLimit = indeed.RateLimit{
+		Rate:    *rate,
+		Burst:   1,
+		Retries: *retries,
+		MaxWait: 10 * time.Second,
+	}
+	if *bootstrap != "" {
+		if err := rdap.LoadBootstrap(context.Background(), *bootstrap); err != nil {
+			slog.Warn("using embedded RDAP bootstrap sample", "src", *bootstrap, "err", err)
+		}
+	}
+
 	whois := indeed.NewWHOISClient()
+	whois.IANAServer = *ianaWHOIS
+	if *whoisConf != "" {
+		if err := whois.LoadConfig(*whoisConf); err != nil {
+			return err
+		}
+	}
+	// Servers take precedence over the config file's addresses.
+	if whois.TLDs == nil {
+		whois.TLDs = make(map[string]indeed.WHOISServer)
+	}
+	for tld, server := range servers {
+		conf := whois.TLDs[tld]
+		conf.Addr = server
+		whois.TLDs[tld] = conf
+	}
+	if whois.Charsets == nil {
+		whois.Charsets = make(map[string]string)
+	}
+	for host, charset := range charsets {
+		whois.Charsets[host] = charset
+	}
+	whois.Referrals = *whoisRefs
+	whois.DialTimeout = *whoisDial
+	whois.ReadTimeout = *whoisRead
+	whois.DefaultLimit = indeed.RateLimit{
+		Burst:   1,
+		Retries: *whoisRetry,
+		MaxWait: 10 * time.Second,
+	}
 	feedHandler := indeed.LogHandler(indeed.NewFeedHandler(rdap, whois), slog.Default())
 	http.Handle("/feed", feedHandler)
 
 	return http.ListenAndServe(*addr, nil)
 }
+
+func rdapOptions() ([]indeed.RDAPOption, error) {
+	opts := []indeed.RDAPOption{
+		indeed.WithTimeout(*timeout),
+		indeed.WithUserAgent(*userAgent),
+	}
+
+	for host, token := range bearer {
+		opts = append(opts, indeed.WithCredentials(host, indeed.BearerToken(token)))
+	}
+
+	if *proxy != "" {
+		u, err := url.Parse(*proxy)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, indeed.WithProxy(u))
+	}
+
+	if *caFile != "" {
+		b, err := os.ReadFile(*caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("no certificates in %q", *caFile)
+		}
+		opts = append(opts, indeed.WithRootCAs(pool))
+	}
+
+	if *certFile != "" || *keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(*certFile, *keyFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, indeed.WithClientCertificates(cert))
+	}
+
+	return opts, nil
+}
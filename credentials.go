@@ -0,0 +1,181 @@
+package indeed
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	urlpkg "net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Credentials interface {
+	Transport(base http.RoundTripper) http.RoundTripper
+}
+
+func WithCredentials(host string, creds Credentials) RDAPOption {
+	return func(o *rdapOptions) {
+		if o.credentials == nil {
+			o.credentials = make(map[string]Credentials)
+		}
+		o.credentials[strings.ToLower(host)] = creds
+	}
+}
+
+type BearerToken string
+
+func (t BearerToken) Transport(base http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "Bearer "+string(t))
+		return base.RoundTrip(req)
+	})
+}
+
+type ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+func (c *ClientCredentials) Transport(base http.RoundTripper) http.RoundTripper {
+	return &clientCredentialsTransport{
+		rt:     base,
+		creds:  c,
+		client: &http.Client{Transport: base},
+	}
+}
+
+var errCertTransport = errors.New("client certificate needs an *http.Transport")
+
+type ClientCertificate struct {
+	Certificate tls.Certificate
+}
+
+// Transport clones base with the certificate added to its TLS config.
+// Requests fail with errCertTransport if base is not an *http.Transport,
+// since a wrapped transport's TLS config cannot be reached.
+func (c *ClientCertificate) Transport(base http.RoundTripper) http.RoundTripper {
+	t, ok := base.(*http.Transport)
+	if !ok {
+		return roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errCertTransport
+		})
+	}
+	t = t.Clone()
+
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	t.TLSClientConfig.Certificates = []tls.Certificate{c.Certificate}
+
+	return t
+}
+
+type clientCredentialsTransport struct {
+	rt     http.RoundTripper
+	creds  *ClientCredentials
+	client *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func (t *clientCredentialsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.accessToken(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	res, err := t.rt.RoundTrip(req)
+	if err == nil && res.StatusCode == http.StatusUnauthorized {
+		t.mu.Lock()
+		t.token = ""
+		t.mu.Unlock()
+	}
+	return res, err
+}
+
+func (t *clientCredentialsTransport) accessToken(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != "" && (t.expires.IsZero() || time.Now().Before(t.expires)) {
+		return t.token, nil
+	}
+
+	form := urlpkg.Values{"grant_type": {"client_credentials"}}
+	if len(t.creds.Scopes) > 0 {
+		form.Set("scope", strings.Join(t.creds.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.creds.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(urlpkg.QueryEscape(t.creds.ClientID), urlpkg.QueryEscape(t.creds.ClientSecret))
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint: unexpected response status %q", res.Status)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if body.AccessToken == "" {
+		return "", fmt.Errorf("token endpoint: no access token")
+	}
+
+	t.token = body.AccessToken
+	t.expires = time.Time{}
+	if body.ExpiresIn > 0 {
+		// Refresh a little early so that a token does not expire in flight.
+		t.expires = time.Now().Add(time.Duration(body.ExpiresIn)*time.Second - 10*time.Second)
+	}
+
+	return t.token, nil
+}
+
+// hostTransport routes requests to a per-host transport, so credentials
+// are only ever sent to the server they were configured for.
+type hostTransport struct {
+	rt    http.RoundTripper
+	hosts map[string]http.RoundTripper
+}
+
+func (t *hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := strings.ToLower(req.URL.Host)
+	if rt, ok := t.hosts[host]; ok {
+		return rt.RoundTrip(req)
+	}
+	if rt, ok := t.hosts[strings.ToLower(req.URL.Hostname())]; ok {
+		return rt.RoundTrip(req)
+	}
+	return t.rt.RoundTrip(req)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
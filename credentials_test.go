@@ -0,0 +1,165 @@
+package indeed
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	urlpkg "net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCredentials(t *testing.T) {
+	var tokens atomic.Int32
+	token := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "brand" || secret != "protection" || r.FormValue("grant_type") != "client_credentials" {
+			http.Error(w, "invalid client", http.StatusUnauthorized)
+			return
+		}
+		tokens.Add(1)
+		fmt.Fprint(w, `{"access_token": "secret", "token_type": "Bearer", "expires_in": 3600}`)
+	}))
+	defer token.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			rdapErrorHandler(w, http.StatusForbidden)
+			return
+		}
+		rdapHandler(w, r)
+	}))
+	defer server.Close()
+
+	host := mustParseURL(t, server.URL).Host
+
+	tests := []struct {
+		description string
+		opts        []RDAPOption
+		want        error
+	}{
+		{"anonymous", nil, ErrRDAPForbidden},
+		{"bearer token", []RDAPOption{WithCredentials(host, BearerToken("secret"))}, nil},
+		{"other host", []RDAPOption{WithCredentials("rdap.example", BearerToken("secret"))}, ErrRDAPForbidden},
+		{"client credentials", []RDAPOption{WithCredentials(host, &ClientCredentials{
+			TokenURL:     token.URL,
+			ClientID:     "brand",
+			ClientSecret: "protection",
+		})}, nil},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			client := NewRDAPClient(server.URL, tc.opts...)
+
+			for i := 0; i < 2; i++ {
+				if _, err := client.Resolve(context.Background(), "example.com"); !errors.Is(err, tc.want) {
+					t.Fatalf("got: %v; want: %v", err, tc.want)
+				}
+			}
+		})
+	}
+
+	if got := tokens.Load(); got != 1 {
+		t.Fatalf("got: %d token requests; want: 1", got)
+	}
+}
+
+func TestClientCertificate(t *testing.T) {
+	cert := testCertificate(t)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(rdapHandler))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	// The anonymous client fails the handshake by design; keep the server quiet.
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	host := mustParseURL(t, server.URL).Host
+
+	tests := []struct {
+		description string
+		opts        []RDAPOption
+		ok          bool
+	}{
+		{"anonymous", []RDAPOption{WithRootCAs(pool)}, false},
+		{"client certificate", []RDAPOption{WithRootCAs(pool), WithCredentials(host, &ClientCertificate{cert})}, true},
+		{"client certificates option", []RDAPOption{WithRootCAs(pool), WithClientCertificates(cert)}, true},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			client := NewRDAPClient(server.URL, tc.opts...)
+
+			_, err := client.Resolve(context.Background(), "example.com")
+			if (err == nil) != tc.ok {
+				t.Fatalf("got: %v; want ok: %t", err, tc.ok)
+			}
+		})
+	}
+}
+
+func TestClientCertificateTransport(t *testing.T) {
+	var called bool
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return nil, errors.New("unexpected request")
+	})
+
+	rt := (&ClientCertificate{testCertificate(t)}).Transport(base)
+
+	req := httptest.NewRequest(http.MethodGet, "https://rdap.example/domain/example.com", nil)
+	if _, err := rt.RoundTrip(req); !errors.Is(err, errCertTransport) {
+		t.Fatalf("got: %v; want: %v", err, errCertTransport)
+	}
+	if called {
+		t.Fatal("got: request sent without certificate")
+	}
+}
+
+func testCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "indeed"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+	}
+}
+
+func mustParseURL(t *testing.T, s string) *urlpkg.URL {
+	u, err := urlpkg.Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
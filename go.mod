@@ -2,4 +2,8 @@ module github.com/axeljohnsson/indeed
 
 go 1.21
 
-require golang.org/x/sync v0.3.0
+require (
+	golang.org/x/net v0.21.0
+	golang.org/x/sync v0.3.0
+	golang.org/x/text v0.14.0
+)
@@ -8,7 +8,6 @@ import (
 	"log/slog"
 	"net/http"
 	urlpkg "net/url"
-	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -16,9 +15,15 @@ import (
 )
 
 const (
-	paramQ   = "q"
-	paramMSM = "msm"
-	paramOp  = "op"
+	paramQ       = "q"
+	paramMSM     = "msm"
+	paramOp      = "op"
+	paramAction  = "action"
+	paramType    = "type"
+	paramSearch  = "search"
+	paramBy      = "by"
+	paramNotices = "notices"
+	paramTLD     = "tld"
 )
 
 var (
@@ -26,18 +31,28 @@ var (
 	errNoParam  = errors.New("no value")
 )
 
-var updateActionRE = regexp.MustCompile("last update of (RDAP|WHOIS) database")
-
 type FeedHandler struct {
-	r Resolver
+	r       Resolver
+	objects map[ObjectClass]Resolver
+	search  Searcher
 }
 
 func NewFeedHandler(rdap *RDAPClient, whois *WHOISClient) *FeedHandler {
 	r := MultiResolver([]Resolver{
 		rdap,
-		TryResolver(whois, errNoServer),
+		TryResolver(TryResolver(whois, errNoServer), ErrWHOISNotFound),
 	})
-	return &FeedHandler{r}
+
+	objects := make(map[ObjectClass]Resolver)
+	for _, class := range []ObjectClass{ClassNameserver, ClassEntity, ClassIP, ClassAutnum} {
+		objects[class] = TrackResolver(rdap.Resolver(class), NewMemoryStore())
+	}
+
+	return &FeedHandler{
+		r:       TrackResolver(r, NewMemoryStore()),
+		objects: objects,
+		search:  TrackSearcher(rdap, NewMemoryStore()),
+	}
 }
 
 func (h *FeedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -46,7 +61,18 @@ func (h *FeedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	names, err := h.names(r.URL.Query())
+	if r.URL.Query().Has(paramSearch) {
+		h.serveSearch(w, r)
+		return
+	}
+
+	class, resolver, err := h.resolver(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	names, err := h.names(r.URL.Query(), class)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -58,41 +84,142 @@ func (h *FeedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	domains, err := ResolveDomains(r.Context(), h.r, names)
+	actions, err := h.actions(r.URL.Query())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	notices, err := h.notices(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	domains, err := ResolveDomains(r.Context(), resolver, names)
+	if err != nil {
+		h.error(w, err)
 		return
 	}
 
 	var match int
 	for _, name := range names {
 		for _, domain := range domains {
-			if strings.ToLower(domain.Name) == name {
+			if canonical, _ := canonicalKey(class, domain.Name); canonical == name {
 				match++
 				break
 			}
 		}
 	}
 	if match < msm {
-		http.Error(w, "domain(s) not found", http.StatusNotFound)
+		http.Error(w, fmt.Sprintf("%s(s) not found", strings.ToLower(class.title())), http.StatusNotFound)
 		return
 	}
 
-	feed, err := h.convert(names, domains)
+	feed, err := h.convert(class, names, actions, notices, domains)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	h.encode(w, feed)
+}
+
+func (h *FeedHandler) serveSearch(w http.ResponseWriter, r *http.Request) {
+	field, pattern, err := h.pattern(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tld, err := h.tld(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	actions, err := h.actions(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	notices, err := h.notices(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	domains, err := h.search.Search(r.Context(), field, pattern, tld)
+	if err != nil {
+		h.error(w, err)
+		return
+	}
+
+	h.encode(w, h.convertSearch(field, pattern, tld, actions, notices, domains))
+}
+
+func (h *FeedHandler) encode(w http.ResponseWriter, feed *RSSFeed) {
 	w.Header().Add("Content-Type", "application/xml")
 
-	if xml.NewEncoder(w).Encode(feed); err != nil {
+	if err := xml.NewEncoder(w).Encode(feed); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 }
 
-func (h *FeedHandler) names(params urlpkg.Values) ([]string, error) {
+func (h *FeedHandler) error(w http.ResponseWriter, err error) {
+	var rdapErr *RDAPError
+	if errors.As(err, &rdapErr) && rdapErr.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(rdapErr.RetryAfter.Round(time.Second).Seconds())))
+	}
+	http.Error(w, err.Error(), h.errorStatus(err))
+}
+
+func (h *FeedHandler) errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrRDAPRateLimited), errors.Is(err, ErrWHOISRateLimited):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, ErrWHOISTimeout):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, ErrRDAPForbidden), errors.Is(err, ErrRDAPServer), errors.Is(err, ErrRDAPMalformed):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *FeedHandler) resolver(params urlpkg.Values) (ObjectClass, Resolver, error) {
+	if !params.Has(paramType) {
+		return ClassDomain, h.r, nil
+	}
+
+	value := params.Get(paramType)
+	class, err := ParseObjectClass(value)
+	if err != nil {
+		return "", nil, &paramError{
+			name:  paramType,
+			value: value,
+			err:   errBadParam,
+		}
+	}
+
+	if class == ClassDomain {
+		return class, h.r, nil
+	}
+
+	r, ok := h.objects[class]
+	if !ok {
+		return "", nil, &paramError{
+			name:  paramType,
+			value: value,
+			err:   errBadParam,
+		}
+	}
+	return class, r, nil
+}
+
+func (h *FeedHandler) names(params urlpkg.Values, class ObjectClass) ([]string, error) {
 	if !params.Has(paramQ) {
 		return nil, &paramError{
 			name: paramQ,
@@ -100,9 +227,17 @@ func (h *FeedHandler) names(params urlpkg.Values) ([]string, error) {
 		}
 	}
 
-	names := params[paramQ]
-	for i, name := range names {
-		names[i] = strings.ToLower(name)
+	names := make([]string, len(params[paramQ]))
+	for i, value := range params[paramQ] {
+		name, err := canonicalKey(class, value)
+		if err != nil {
+			return nil, &paramError{
+				name:  paramQ,
+				value: value,
+				err:   errBadParam,
+			}
+		}
+		names[i] = name
 	}
 
 	sort.Strings(names)
@@ -110,6 +245,68 @@ func (h *FeedHandler) names(params urlpkg.Values) ([]string, error) {
 	return names, nil
 }
 
+func (h *FeedHandler) pattern(params urlpkg.Values) (SearchField, string, error) {
+	field := SearchName
+	if params.Has(paramBy) {
+		value := params.Get(paramBy)
+		var err error
+		if field, err = ParseSearchField(value); err != nil {
+			return "", "", &paramError{
+				name:  paramBy,
+				value: value,
+				err:   errBadParam,
+			}
+		}
+	}
+
+	value := params.Get(paramSearch)
+	pattern, err := canonicalPattern(field, value)
+	if err != nil {
+		return "", "", &paramError{
+			name:  paramSearch,
+			value: value,
+			err:   errBadParam,
+		}
+	}
+	return field, pattern, nil
+}
+
+// tld returns the top-level domain whose registry to search, which may
+// be empty.
+func (h *FeedHandler) tld(params urlpkg.Values) (string, error) {
+	if !params.Has(paramTLD) {
+		return "", nil
+	}
+
+	value := params.Get(paramTLD)
+	tld, err := canonicalTLD(value)
+	if err != nil {
+		return "", &paramError{
+			name:  paramTLD,
+			value: value,
+			err:   errBadParam,
+		}
+	}
+	return tld, nil
+}
+
+func (h *FeedHandler) notices(params urlpkg.Values) (bool, error) {
+	if !params.Has(paramNotices) {
+		return false, nil
+	}
+
+	value := params.Get(paramNotices)
+	notices, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, &paramError{
+			name:  paramNotices,
+			value: value,
+			err:   errBadParam,
+		}
+	}
+	return notices, nil
+}
+
 func (h *FeedHandler) msm(params urlpkg.Values) (int, error) {
 	if params.Has(paramMSM) {
 		value := params.Get(paramMSM)
@@ -142,17 +339,135 @@ func (h *FeedHandler) msm(params urlpkg.Values) (int, error) {
 	return len(params[paramQ]), nil
 }
 
-func (h *FeedHandler) convert(names []string, domains []Domain) (*RSSFeed, error) {
+func (h *FeedHandler) actions(params urlpkg.Values) ([]EventAction, error) {
+	var actions []EventAction
+	for _, value := range params[paramAction] {
+		action := ParseEventAction(value)
+		if action == ActionOther && normalizeAction(value) != string(ActionOther) {
+			return nil, &paramError{
+				name:  paramAction,
+				value: value,
+				err:   errBadParam,
+			}
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+func (h *FeedHandler) convert(class ObjectClass, names []string, actions []EventAction, notices bool, domains []Domain) (*RSSFeed, error) {
+	items := h.items(actions, notices, domains)
+
+	var link urlpkg.URL
+	link.Path = "/feed"
+	params := urlpkg.Values{paramQ: names}
+	for _, action := range actions {
+		params.Add(paramAction, string(action))
+	}
+	if class != ClassDomain {
+		params.Set(paramType, string(class))
+	}
+	if notices {
+		params.Set(paramNotices, "true")
+	}
+	link.RawQuery = params.Encode()
+
+	display := make([]string, len(names))
+	for i, name := range names {
+		display[i] = (&Domain{Class: class, Name: name}).DisplayName()
+	}
+
+	desc := fmt.Sprintf("%s events for: %s.", class.title(), strings.Join(display, ", "))
+	if notices {
+		desc = h.withNotices(desc, domains)
+	}
+
+	return &RSSFeed{
+		Version:     "2.0",
+		Title:       fmt.Sprintf("%s Events", class.title()),
+		Link:        link.String(),
+		Description: desc,
+		Items:       items,
+	}, nil
+}
+
+func (h *FeedHandler) convertSearch(field SearchField, pattern, tld string, actions []EventAction, notices bool, domains []Domain) *RSSFeed {
+	var link urlpkg.URL
+	link.Path = "/feed"
+	params := urlpkg.Values{paramSearch: {pattern}}
+	if field != SearchName {
+		params.Set(paramBy, string(field))
+	}
+	if tld != "" {
+		params.Set(paramTLD, tld)
+	}
+	for _, action := range actions {
+		params.Add(paramAction, string(action))
+	}
+	if notices {
+		params.Set(paramNotices, "true")
+	}
+	link.RawQuery = params.Encode()
+
+	desc := fmt.Sprintf("Domain events for %s search: %s.", field, pattern)
+	if notices {
+		desc = h.withNotices(desc, domains)
+	}
+
+	return &RSSFeed{
+		Version:     "2.0",
+		Title:       "Domain Search Events",
+		Link:        link.String(),
+		Description: desc,
+		Items:       h.items(actions, notices, domains),
+	}
+}
+
+// withNotices appends the distinct notices of domains to a channel
+// description.
+func (h *FeedHandler) withNotices(desc string, domains []Domain) string {
+	seen := make(map[string]bool)
+	for _, domain := range domains {
+		for _, notice := range domain.Notices {
+			if s := notice.String(); s != "" && !seen[s] {
+				seen[s] = true
+				desc = fmt.Sprintf("%s\n%s", desc, s)
+			}
+		}
+	}
+	return desc
+}
+
+func (h *FeedHandler) items(actions []EventAction, notices bool, domains []Domain) []RSSItem {
+	include := func(action EventAction) bool {
+		if len(actions) == 0 {
+			return action != ActionLastUpdate && action != ActionLastWHOISUpdate
+		}
+		for _, a := range actions {
+			if a == action {
+				return true
+			}
+		}
+		return false
+	}
+
 	items := make([]RSSItem, 0)
 	for _, domain := range domains {
 		for _, event := range domain.Events {
-			if updateActionRE.MatchString(event.Action) {
+			if !include(event.Action) {
 				continue
 			}
+			desc := h.itemDescription(&domain, &event)
+			if notices {
+				for _, remark := range domain.Remarks {
+					desc = fmt.Sprintf("%s; remark: %s", desc, remark)
+				}
+			}
 			items = append(items, RSSItem{
 				Link:        domain.Link,
-				Description: fmt.Sprintf("%s: %s", strings.ToLower(domain.Name), event.Action),
+				Description: desc,
 				Author:      event.Actor,
+				Categories:  domain.Status,
 				GUID:        h.itemGUID(&domain, &event),
 				PubDate:     RSSTime{event.Date},
 			})
@@ -161,18 +476,43 @@ func (h *FeedHandler) convert(names []string, domains []Domain) (*RSSFeed, error
 	sort.Slice(items, func(i, j int) bool {
 		return items[i].PubDate.After(items[j].PubDate.Time)
 	})
+	return items
+}
 
-	var link urlpkg.URL
-	link.Path = "/feed"
-	link.RawQuery = urlpkg.Values{paramQ: names}.Encode()
-
-	return &RSSFeed{
-		Version:     "2.0",
-		Title:       "Domain Events",
-		Link:        link.String(),
-		Description: fmt.Sprintf("Domain events for: %s.", strings.Join(names, ", ")),
-		Items:       items,
-	}, nil
+func (h *FeedHandler) itemDescription(domain *Domain, event *Event) string {
+	desc := fmt.Sprintf("%s: %s", domain.DisplayName(), event.Action)
+	if event.Detail != "" {
+		desc = fmt.Sprintf("%s: %s", desc, event.Detail)
+	}
+	if event.Source != "" {
+		source := event.Source
+		if u, err := urlpkg.Parse(source); err == nil && u.Host != "" {
+			source = u.Host
+		}
+		desc = fmt.Sprintf("%s (via %s)", desc, source)
+	}
+	for _, contact := range domain.Contacts {
+		if !contact.HasRole("abuse") {
+			continue
+		}
+		var parts []string
+		for _, part := range []string{contact.Email, contact.Phone} {
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+		if len(parts) > 0 {
+			desc = fmt.Sprintf("%s; abuse contact: %s", desc, strings.Join(parts, ", "))
+		}
+	}
+	if len(domain.Redacted) > 0 {
+		names := make([]string, len(domain.Redacted))
+		for i, r := range domain.Redacted {
+			names[i] = r.Name
+		}
+		desc = fmt.Sprintf("%s; redacted: %s", desc, strings.Join(names, ", "))
+	}
+	return desc
 }
 
 func (h *FeedHandler) itemGUID(domain *Domain, event *Event) string {
@@ -180,20 +520,51 @@ func (h *FeedHandler) itemGUID(domain *Domain, event *Event) string {
 	w.Write([]byte(strings.ToLower(domain.Name)))
 	w.Write([]byte(event.Action))
 	w.Write([]byte(event.Date.Format(time.RFC3339)))
+	if event.Source != "" {
+		w.Write([]byte(event.Source))
+	}
+	if event.Detail != "" {
+		w.Write([]byte(event.Detail))
+	}
 	return fmt.Sprintf("%x", w.Sum(nil))
 }
 
 func LogHandler(h http.Handler, logger *slog.Logger) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
-		h.ServeHTTP(w, r)
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, r)
 		duration := time.Now().Sub(start)
 
 		req := slog.Group("req", slog.String("method", r.Method), slog.String("url", r.URL.String()))
-		logger.InfoContext(r.Context(), "processed", req, slog.Duration("duration", duration))
+		res := slog.Group("res", slog.Int("status", rec.status))
+		if rec.status >= http.StatusBadRequest {
+			res = slog.Group("res", slog.Int("status", rec.status), slog.String("error", strings.TrimSpace(rec.body.String())))
+		}
+		logger.InfoContext(r.Context(), "processed", req, res, slog.Duration("duration", duration))
 	})
 }
 
+const maxErrorBody = 1024
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	body   strings.Builder
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status >= http.StatusBadRequest && r.body.Len() < maxErrorBody {
+		r.body.Write(b[:min(len(b), maxErrorBody-r.body.Len())])
+	}
+	return r.ResponseWriter.Write(b)
+}
+
 type paramError struct {
 	name  string
 	value string
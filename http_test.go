@@ -7,7 +7,6 @@ import (
 	"net/http/httptest"
 	urlpkg "net/url"
 	"reflect"
-	"strings"
 	"testing"
 	"time"
 )
@@ -27,9 +26,12 @@ func TestHTTPBody(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	link, err := urlpkg.JoinPath(RDAPBaseURL, "domain", strings.ToUpper(v.Get(paramQ)))
-	if err != nil {
-		t.Fatal(err)
+	link := "https://rdap.verisign.com/com/v1/domain/EXAMPLE.COM"
+
+	status := []string{
+		"client delete prohibited",
+		"client transfer prohibited",
+		"client update prohibited",
 	}
 
 	want := RSSFeed{
@@ -42,18 +44,21 @@ func TestHTTPBody(t *testing.T) {
 			{
 				Link:        link,
 				Description: "example.com: expiration",
+				Categories:  status,
 				GUID:        "0e7b8746deb1b3df50b53bd3fa1df6f795e130088f3dbee4fbcd559b99ea7e46",
 				PubDate:     RSSTime{time.Date(2024, 8, 13, 4, 0, 0, 0, time.UTC)},
 			},
 			{
 				Link:        link,
 				Description: "example.com: last changed",
+				Categories:  status,
 				GUID:        "264aaecf302ed10f175731ded269a76e2ac202212ac70cf6e73977e6ba033f5b",
 				PubDate:     RSSTime{time.Date(2023, 8, 14, 7, 1, 0, 0, time.UTC)},
 			},
 			{
 				Link:        link,
 				Description: "example.com: registration",
+				Categories:  status,
 				GUID:        "8c0e7bcead41a573c598c2ab9ae7e95fde486b0d7307b115a1da9b6d6fbb8c4a",
 				PubDate:     RSSTime{time.Date(1995, 8, 14, 4, 0, 0, 0, time.UTC)},
 			},
@@ -65,6 +70,127 @@ func TestHTTPBody(t *testing.T) {
 	}
 }
 
+func TestHTTPIDNA(t *testing.T) {
+	v := urlpkg.Values{}
+	v.Set(paramQ, "Bücher.com")
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.URL.RawQuery = v.Encode()
+
+	res := testLookup(req)
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("got: %d; want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	var got RSSFeed
+	if err := xml.NewDecoder(res.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "/feed?q=xn--bcher-kva.com"; got.Link != want {
+		t.Fatalf("got: %q; want: %q", got.Link, want)
+	}
+	if want := "Domain events for: bücher.com."; got.Description != want {
+		t.Fatalf("got: %q; want: %q", got.Description, want)
+	}
+	if want := "bücher.com: expiration"; len(got.Items) == 0 || got.Items[0].Description != want {
+		t.Fatalf("got: %v; want: %q", got.Items, want)
+	}
+}
+
+func TestHTTPAction(t *testing.T) {
+	v := urlpkg.Values{}
+	v.Set(paramQ, "example.com")
+	v.Add(paramAction, "Expiration")
+	v.Add(paramAction, "lastChanged")
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.URL.RawQuery = v.Encode()
+
+	res := testLookup(req)
+	defer res.Body.Close()
+
+	var got RSSFeed
+	if err := xml.NewDecoder(res.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "/feed?action=expiration&action=last+changed&q=example.com"; got.Link != want {
+		t.Fatalf("got: %q; want: %q", got.Link, want)
+	}
+
+	var descriptions []string
+	for _, item := range got.Items {
+		descriptions = append(descriptions, item.Description)
+	}
+	want := []string{"example.com: expiration", "example.com: last changed"}
+	if !reflect.DeepEqual(descriptions, want) {
+		t.Fatalf("got: %v; want: %v", descriptions, want)
+	}
+}
+
+func TestHTTPNotices(t *testing.T) {
+	v := urlpkg.Values{}
+	v.Set(paramQ, "example.com")
+	v.Set(paramNotices, "1")
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.URL.RawQuery = v.Encode()
+
+	res := testLookup(req)
+	defer res.Body.Close()
+
+	var got RSSFeed
+	if err := xml.NewDecoder(res.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "/feed?notices=true&q=example.com"; got.Link != want {
+		t.Fatalf("got: %q; want: %q", got.Link, want)
+	}
+	want := "Domain events for: example.com.\n" +
+		"Terms of Use: Service subject to Terms of Use.\n" +
+		"Status Codes: For more information on domain status codes, please visit https://icann.org/epp\n" +
+		"RDDS Inaccuracy Complaint Form: URL of the ICANN RDDS Inaccuracy Complaint Form: https://icann.org/wicf"
+	if got.Description != want {
+		t.Fatalf("got: %q; want: %q", got.Description, want)
+	}
+}
+
+func TestHTTPSearch(t *testing.T) {
+	v := urlpkg.Values{}
+	v.Set(paramSearch, "Example*.com")
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.URL.RawQuery = v.Encode()
+
+	res := testFeedLookup(req)
+	defer res.Body.Close()
+
+	var got RSSFeed
+	if err := xml.NewDecoder(res.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "/feed?search=example%2A.com"; got.Link != want {
+		t.Fatalf("got: %q; want: %q", got.Link, want)
+	}
+	if want := "Domain events for name search: example*.com."; got.Description != want {
+		t.Fatalf("got: %q; want: %q", got.Description, want)
+	}
+
+	var descriptions []string
+	for _, item := range got.Items {
+		descriptions = append(descriptions, item.Description)
+	}
+	want := []string{"example-shop.com: registration", "example.com: registration"}
+	if !reflect.DeepEqual(descriptions, want) {
+		t.Fatalf("got: %v; want: %v", descriptions, want)
+	}
+}
+
 func TestHTTPStatusCode(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -94,6 +220,32 @@ func TestHTTPStatusCode(t *testing.T) {
 			urlpkg.Values{},
 			http.StatusBadRequest,
 		},
+		{
+			"invalid type",
+			http.MethodGet,
+			urlpkg.Values{
+				paramQ:    []string{"example.com"},
+				paramType: []string{"network"},
+			},
+			http.StatusBadRequest,
+		},
+		{
+			"invalid action",
+			http.MethodGet,
+			urlpkg.Values{
+				paramQ:      []string{"example.com"},
+				paramAction: []string{"auction closed"},
+			},
+			http.StatusBadRequest,
+		},
+		{
+			"invalid name",
+			http.MethodGet,
+			urlpkg.Values{
+				paramQ: []string{"exa mple.com"},
+			},
+			http.StatusBadRequest,
+		},
 		{
 			"not found",
 			http.MethodGet,
@@ -102,6 +254,31 @@ func TestHTTPStatusCode(t *testing.T) {
 			},
 			http.StatusNotFound,
 		},
+		{
+			"rate limited",
+			http.MethodGet,
+			urlpkg.Values{
+				paramQ: []string{"429.com"},
+			},
+			http.StatusServiceUnavailable,
+		},
+		{
+			"malformed response",
+			http.MethodGet,
+			urlpkg.Values{
+				paramQ: []string{"malformed.com"},
+			},
+			http.StatusBadGateway,
+		},
+		{
+			"invalid notices",
+			http.MethodGet,
+			urlpkg.Values{
+				paramQ:       []string{"example.com"},
+				paramNotices: []string{"sometimes"},
+			},
+			http.StatusBadRequest,
+		},
 	}
 	for _, tc := range tests {
 		tc := tc
@@ -119,6 +296,76 @@ func TestHTTPStatusCode(t *testing.T) {
 	}
 }
 
+// TestHTTPFeedStatusCode covers requests served by the resolvers and
+// searcher of NewFeedHandler.
+func TestHTTPFeedStatusCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		params urlpkg.Values
+		want   int
+	}{
+		{
+			"ip network",
+			http.MethodGet,
+			urlpkg.Values{
+				paramQ:    []string{"192.0.2.0/24"},
+				paramType: []string{"ip"},
+			},
+			http.StatusOK,
+		},
+		{
+			"search",
+			http.MethodGet,
+			urlpkg.Values{
+				paramSearch: []string{"example*.com"},
+			},
+			http.StatusOK,
+		},
+		{
+			"invalid search field",
+			http.MethodGet,
+			urlpkg.Values{
+				paramSearch: []string{"example*.com"},
+				paramBy:     []string{"registrant"},
+			},
+			http.StatusBadRequest,
+		},
+		{
+			"invalid search tld",
+			http.MethodGet,
+			urlpkg.Values{
+				paramSearch: []string{"ns1.example.net"},
+				paramBy:     []string{"nsLdhName"},
+				paramTLD:    []string{"example.com"},
+			},
+			http.StatusBadRequest,
+		},
+		{
+			"invalid search pattern",
+			http.MethodGet,
+			urlpkg.Values{
+				paramSearch: []string{"exa mple*.com"},
+			},
+			http.StatusBadRequest,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/", nil)
+			req.URL.RawQuery = tc.params.Encode()
+
+			res := testFeedLookup(req)
+			defer res.Body.Close()
+
+			if res.StatusCode != tc.want {
+				t.Fatalf("got: %d; want: %d", res.StatusCode, tc.want)
+			}
+		})
+	}
+}
+
 func TestMSM(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -192,11 +439,88 @@ func TestMSM(t *testing.T) {
 	}
 }
 
+func TestItemDescription(t *testing.T) {
+	tests := []struct {
+		description string
+		domain      Domain
+		event       Event
+		want        string
+	}{
+		{
+			"plain",
+			Domain{Name: "EXAMPLE.COM"},
+			Event{Action: "registration"},
+			"example.com: registration",
+		},
+		{
+			"detail",
+			Domain{Name: "EXAMPLE.COM"},
+			Event{Action: "status added", Detail: "client hold"},
+			"example.com: status added: client hold",
+		},
+		{
+			"source",
+			Domain{Name: "EXAMPLE.COM"},
+			Event{Action: "transfer", Source: "https://rdap.registrar.example/domain/EXAMPLE.COM"},
+			"example.com: transfer (via rdap.registrar.example)",
+		},
+		{
+			"abuse contact",
+			Domain{
+				Name: "EXAMPLE.COM",
+				Contacts: []Entity{
+					{Roles: []string{"abuse"}, Email: "abuse@registrar.example", Phone: "+1.5555551234"},
+				},
+			},
+			Event{Action: "registration"},
+			"example.com: registration; abuse contact: abuse@registrar.example, +1.5555551234",
+		},
+		{
+			"redacted",
+			Domain{
+				Name: "EXAMPLE.COM",
+				Redacted: []Redaction{
+					{Name: "Registrant Name", Method: RedactionRemoval},
+					{Name: "Registrant Email", Method: RedactionEmptyValue},
+				},
+			},
+			Event{Action: "registration"},
+			"example.com: registration; redacted: Registrant Name, Registrant Email",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			h := FeedHandler{}
+			if got := h.itemDescription(&tc.domain, &tc.event); got != tc.want {
+				t.Fatalf("got: %q; want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
 func testLookup(r *http.Request) *http.Response {
 	server := httptest.NewServer(http.HandlerFunc(rdapHandler))
 	defer server.Close()
 
-	h := &FeedHandler{NewRDAPClient(server.URL)}
+	h := &FeedHandler{r: NewRDAPClient(server.URL)}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	return w.Result()
+}
+
+// testFeedLookup serves r with NewFeedHandler, whose WHOIS client knows
+// no servers.
+func testFeedLookup(r *http.Request) *http.Response {
+	server := httptest.NewServer(http.HandlerFunc(rdapHandler))
+	defer server.Close()
+
+	whois := NewWHOISClient()
+	whois.IANAServer = ""
+
+	h := NewFeedHandler(NewRDAPClient(server.URL), whois)
 
 	w := httptest.NewRecorder()
 	h.ServeHTTP(w, r)
@@ -0,0 +1,78 @@
+package indeed
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var errBadJCard = errors.New("malformed jCard")
+
+type jCard map[string][]string
+
+// parseJCard decodes a jCard (RFC 7095) into its property values by name.
+// Structured values such as "adr" or "n" are flattened in order.
+func parseJCard(raw json.RawMessage) (jCard, error) {
+	card := make(jCard)
+	if len(raw) == 0 {
+		return card, nil
+	}
+
+	var body []json.RawMessage
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, err
+	}
+	if len(body) != 2 {
+		return nil, errBadJCard
+	}
+
+	var props [][]json.RawMessage
+	if err := json.Unmarshal(body[1], &props); err != nil {
+		return nil, err
+	}
+
+	for _, prop := range props {
+		if len(prop) < 4 {
+			return nil, errBadJCard
+		}
+
+		var name string
+		if err := json.Unmarshal(prop[0], &name); err != nil {
+			return nil, err
+		}
+		name = strings.ToLower(name)
+
+		for _, value := range prop[3:] {
+			card[name] = append(card[name], jCardValues(value)...)
+		}
+	}
+
+	return card, nil
+}
+
+func jCardValues(raw json.RawMessage) []string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return []string{s}
+	}
+
+	var values []json.RawMessage
+	if err := json.Unmarshal(raw, &values); err == nil {
+		var ss []string
+		for _, value := range values {
+			ss = append(ss, jCardValues(value)...)
+		}
+		return ss
+	}
+
+	return nil
+}
+
+func (c jCard) get(name string) string {
+	for _, value := range c[name] {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+package indeed
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJCard(t *testing.T) {
+	raw := []byte(`["vcard", [
+		["version", {}, "text", "4.0"],
+		["fn", {}, "text", "Example Registrar"],
+		["org", {"type": "work"}, "text", "Example Inc."],
+		["adr", {}, "text", ["", "", "", "", "CA", "", "US"]],
+		["tel", {"type": "voice"}, "uri", "tel:+1.5555551234"],
+		["email", {}, "text", "abuse@registrar.example"]
+	]]`)
+
+	got, err := parseJCard(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := jCard{
+		"version": {"4.0"},
+		"fn":      {"Example Registrar"},
+		"org":     {"Example Inc."},
+		"adr":     {"", "", "", "", "CA", "", "US"},
+		"tel":     {"tel:+1.5555551234"},
+		"email":   {"abuse@registrar.example"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got: %v; want: %v", got, want)
+	}
+
+	if got := got.get("adr"); got != "CA" {
+		t.Fatalf("got: %q; want: %q", got, "CA")
+	}
+}
@@ -0,0 +1,45 @@
+package indeed
+
+import "container/list"
+
+// lruCache maps keys to values, dropping the least recently used key
+// once it holds more than the given size. A size of zero means no limit.
+// The zero value is an empty cache. It is not safe for concurrent use.
+type lruCache[V any] struct {
+	m map[string]*list.Element
+	l list.List
+}
+
+type lruEntry[V any] struct {
+	key   string
+	value V
+}
+
+func (c *lruCache[V]) get(key string) (V, bool) {
+	e, ok := c.m[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.l.MoveToFront(e)
+	return e.Value.(*lruEntry[V]).value, true
+}
+
+func (c *lruCache[V]) put(key string, value V, size int) {
+	if e, ok := c.m[key]; ok {
+		e.Value.(*lruEntry[V]).value = value
+		c.l.MoveToFront(e)
+		return
+	}
+
+	if c.m == nil {
+		c.m = make(map[string]*list.Element)
+	}
+	c.m[key] = c.l.PushFront(&lruEntry[V]{key, value})
+
+	if size > 0 && c.l.Len() > size {
+		oldest := c.l.Back()
+		c.l.Remove(oldest)
+		delete(c.m, oldest.Value.(*lruEntry[V]).key)
+	}
+}
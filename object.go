@@ -0,0 +1,98 @@
+package indeed
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+type ObjectClass string
+
+const (
+	ClassDomain     ObjectClass = "domain"
+	ClassNameserver ObjectClass = "nameserver"
+	ClassEntity     ObjectClass = "entity"
+	ClassIP         ObjectClass = "ip"
+	ClassAutnum     ObjectClass = "autnum"
+)
+
+var errBadClass = errors.New("unknown object class")
+
+func ParseObjectClass(s string) (ObjectClass, error) {
+	switch class := ObjectClass(strings.ToLower(s)); class {
+	case ClassDomain, ClassNameserver, ClassEntity, ClassIP, ClassAutnum:
+		return class, nil
+	default:
+		return "", errBadClass
+	}
+}
+
+func (c ObjectClass) title() string {
+	switch c {
+	case ClassNameserver:
+		return "Nameserver"
+	case ClassEntity:
+		return "Entity"
+	case ClassIP:
+		return "IP Network"
+	case ClassAutnum:
+		return "Autonomous System"
+	default:
+		return "Domain"
+	}
+}
+
+// canonicalKey normalizes a lookup key for the object class: names to
+// lowercase A-labels, addresses and prefixes to their canonical text form
+// and autonomous system numbers to "AS" followed by the number.
+func canonicalKey(class ObjectClass, key string) (string, error) {
+	switch class {
+	case ClassDomain, ClassNameserver:
+		return canonicalName(key)
+	case ClassIP:
+		if strings.Contains(key, "/") {
+			prefix, err := netip.ParsePrefix(key)
+			if err != nil {
+				return "", err
+			}
+			return prefix.Masked().String(), nil
+		}
+		addr, err := netip.ParseAddr(key)
+		if err != nil {
+			return "", err
+		}
+		return addr.String(), nil
+	case ClassAutnum:
+		n, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(key), "AS"), 10, 32)
+		if err != nil {
+			return "", err
+		}
+		return "AS" + strconv.FormatUint(n, 10), nil
+	case ClassEntity:
+		if key = strings.TrimSpace(key); key == "" {
+			return "", errors.New("empty handle")
+		}
+		return key, nil
+	default:
+		return "", errBadClass
+	}
+}
+
+// pathKey returns the key as it appears in an RDAP lookup path.
+func pathKey(class ObjectClass, key string) string {
+	if class == ClassAutnum {
+		return strings.TrimPrefix(key, "AS")
+	}
+	return key
+}
+
+type objectResolver struct {
+	c     *RDAPClient
+	class ObjectClass
+}
+
+func (r *objectResolver) Resolve(ctx context.Context, key string) (*Domain, error) {
+	return r.c.ResolveObject(ctx, r.class, key)
+}
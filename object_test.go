@@ -0,0 +1,35 @@
+package indeed
+
+import "testing"
+
+func TestCanonicalKey(t *testing.T) {
+	tests := []struct {
+		class ObjectClass
+		key   string
+		want  string
+		err   bool
+	}{
+		{ClassDomain, "Bücher.de", "xn--bcher-kva.de", false},
+		{ClassNameserver, "A.IANA-SERVERS.NET", "a.iana-servers.net", false},
+		{ClassIP, "192.0.2.1", "192.0.2.1", false},
+		{ClassIP, "192.0.2.1/24", "192.0.2.0/24", false},
+		{ClassIP, "2001:DB8::/32", "2001:db8::/32", false},
+		{ClassIP, "192.0.2", "", true},
+		{ClassAutnum, "as64496", "AS64496", false},
+		{ClassAutnum, "64496", "AS64496", false},
+		{ClassAutnum, "AS-EXAMPLE", "", true},
+		{ClassEntity, "IANA", "IANA", false},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(string(tc.class)+"/"+tc.key, func(t *testing.T) {
+			got, err := canonicalKey(tc.class, tc.key)
+			if (err != nil) != tc.err {
+				t.Fatalf("got: %v; want error: %t", err, tc.err)
+			}
+			if got != tc.want {
+				t.Fatalf("got: %q; want: %q", got, tc.want)
+			}
+		})
+	}
+}
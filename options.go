@@ -0,0 +1,139 @@
+package indeed
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	urlpkg "net/url"
+	"time"
+)
+
+var errOptionsTransport = errors.New("proxy and TLS options need an *http.Transport")
+
+type RDAPOption func(*rdapOptions)
+
+type rdapOptions struct {
+	transport   http.RoundTripper
+	timeout     time.Duration
+	userAgent   string
+	proxy       func(*http.Request) (*urlpkg.URL, error)
+	tls         *tls.Config
+	credentials map[string]Credentials
+}
+
+// WithTransport sets the transport requests are sent with. WithProxy and
+// the TLS options need an *http.Transport; with any other transport,
+// requests fail rather than silently skipping them.
+func WithTransport(transport http.RoundTripper) RDAPOption {
+	return func(o *rdapOptions) {
+		o.transport = transport
+	}
+}
+
+func WithTimeout(timeout time.Duration) RDAPOption {
+	return func(o *rdapOptions) {
+		o.timeout = timeout
+	}
+}
+
+func WithUserAgent(userAgent string) RDAPOption {
+	return func(o *rdapOptions) {
+		o.userAgent = userAgent
+	}
+}
+
+func WithProxy(proxy *urlpkg.URL) RDAPOption {
+	return func(o *rdapOptions) {
+		o.proxy = http.ProxyURL(proxy)
+	}
+}
+
+func WithTLSConfig(config *tls.Config) RDAPOption {
+	return func(o *rdapOptions) {
+		o.tls = config.Clone()
+	}
+}
+
+func WithRootCAs(pool *x509.CertPool) RDAPOption {
+	return func(o *rdapOptions) {
+		o.tlsConfig().RootCAs = pool
+	}
+}
+
+func WithClientCertificates(certs ...tls.Certificate) RDAPOption {
+	return func(o *rdapOptions) {
+		config := o.tlsConfig()
+		config.Certificates = append(config.Certificates, certs...)
+	}
+}
+
+func (o *rdapOptions) tlsConfig() *tls.Config {
+	if o.tls == nil {
+		o.tls = &tls.Config{}
+	}
+	return o.tls
+}
+
+// httpClient builds the client from the options. Proxy and TLS settings
+// apply to the transport if it is an *http.Transport, which is cloned
+// rather than modified. Requests fail with errOptionsTransport otherwise.
+func (o *rdapOptions) httpClient() *http.Client {
+	transport := o.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	if o.proxy != nil || o.tls != nil {
+		t, ok := transport.(*http.Transport)
+		if !ok {
+			return &http.Client{
+				Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+					return nil, errOptionsTransport
+				}),
+			}
+		}
+		t = t.Clone()
+		if o.proxy != nil {
+			t.Proxy = o.proxy
+		}
+		if o.tls != nil {
+			t.TLSClientConfig = o.tls
+		}
+		transport = t
+	}
+
+	if len(o.credentials) > 0 {
+		hosts := make(map[string]http.RoundTripper, len(o.credentials))
+		for host, creds := range o.credentials {
+			hosts[host] = creds.Transport(transport)
+		}
+		transport = &hostTransport{
+			rt:    transport,
+			hosts: hosts,
+		}
+	}
+
+	if o.userAgent != "" {
+		transport = &userAgentTransport{
+			rt:        transport,
+			userAgent: o.userAgent,
+		}
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   o.timeout,
+	}
+}
+
+type userAgentTransport struct {
+	rt        http.RoundTripper
+	userAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.rt.RoundTrip(req)
+}
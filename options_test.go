@@ -0,0 +1,125 @@
+package indeed
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	urlpkg "net/url"
+	"testing"
+	"time"
+)
+
+func TestRDAPOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(rdapHandler))
+	defer server.Close()
+
+	// Untrusted clients fail the handshake by design; keep the server quiet.
+	tls := httptest.NewUnstartedServer(http.HandlerFunc(rdapHandler))
+	tls.Config.ErrorLog = log.New(io.Discard, "", 0)
+	tls.StartTLS()
+	defer tls.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(tls.Certificate())
+
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		rdapHandler(w, r)
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := urlpkg.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+
+	tests := []struct {
+		description string
+		baseURL     string
+		opts        []RDAPOption
+		ok          bool
+	}{
+		{"default", server.URL, nil, true},
+		{"untrusted", tls.URL, nil, false},
+		{"root CAs", tls.URL, []RDAPOption{WithRootCAs(pool)}, true},
+		{"proxy", "http://rdap.invalid/", []RDAPOption{WithProxy(proxyURL)}, true},
+		{"timeout", slow.URL, []RDAPOption{WithTimeout(10 * time.Millisecond)}, false},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			client := NewRDAPClient(tc.baseURL, tc.opts...)
+
+			domain, err := client.Resolve(context.Background(), "example.com")
+			if (err == nil) != tc.ok {
+				t.Fatalf("got: %v; want ok: %t", err, tc.ok)
+			}
+			if tc.ok && domain == nil {
+				t.Fatal("got: nil domain")
+			}
+		})
+	}
+
+	if want := "http://rdap.invalid/domain/example.com"; proxied != want {
+		t.Fatalf("got: %q; want: %q", proxied, want)
+	}
+}
+
+func TestWithTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(rdapHandler))
+	defer server.Close()
+
+	var called bool
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	client := NewRDAPClient(server.URL, WithTransport(transport))
+	if _, err := client.Resolve(context.Background(), "example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if !called {
+		t.Fatal("got: transport not used")
+	}
+
+	called = false
+	client = NewRDAPClient(server.URL, WithTransport(transport), WithRootCAs(x509.NewCertPool()))
+	if _, err := client.Resolve(context.Background(), "example.com"); !errors.Is(err, errOptionsTransport) {
+		t.Fatalf("got: %v; want: %v", err, errOptionsTransport)
+	}
+	if called {
+		t.Fatal("got: request sent without TLS options")
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.UserAgent()
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	client := NewRDAPClient(server.URL, WithUserAgent("indeed-test/1.0"))
+	if _, err := client.Resolve(context.Background(), "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "indeed-test/1.0"; got != want {
+		t.Fatalf("got: %q; want: %q", got, want)
+	}
+}
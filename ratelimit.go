@@ -0,0 +1,118 @@
+package indeed
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type RateLimit struct {
+	Rate    float64
+	Burst   int
+	Retries int
+	Backoff time.Duration
+	MaxWait time.Duration
+}
+
+func (l RateLimit) backoff(attempt int) time.Duration {
+	if l.Backoff <= 0 {
+		return time.Second << attempt
+	}
+	return l.Backoff << attempt
+}
+
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait reserves a token, blocking until it is available. Reservations
+// may drive the bucket negative so that concurrent callers queue up in
+// order instead of racing for the next token.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b.rate <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	delay := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mu.Unlock()
+
+	return sleep(ctx, delay)
+}
+
+// tokenBuckets holds a token bucket per host, created on first use.
+type tokenBuckets struct {
+	mu sync.Mutex
+	m  map[string]*tokenBucket
+}
+
+func (b *tokenBuckets) get(host string, limit RateLimit) *tokenBucket {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.m == nil {
+		b.m = make(map[string]*tokenBucket)
+	}
+
+	bucket, ok := b.m[host]
+	if !ok {
+		bucket = newTokenBucket(limit.Rate, limit.Burst)
+		b.m[host] = bucket
+	}
+	return bucket
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func retryAfter(h http.Header, now time.Time) time.Duration {
+	value := h.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0)
+	}
+
+	return 0
+}
@@ -0,0 +1,94 @@
+package indeed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	b := newTokenBucket(100, 1)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := b.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Fatalf("got: %v; want: >= %v", elapsed, 25*time.Millisecond)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"Fri, 01 Sep 2023 12:00:30 GMT", 30 * time.Second},
+		{"Fri, 01 Sep 2023 11:00:00 GMT", 0},
+		{"soon", 0},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.value, func(t *testing.T) {
+			h := http.Header{}
+			if tc.value != "" {
+				h.Set("Retry-After", tc.value)
+			}
+			if got := retryAfter(h, now); got != tc.want {
+				t.Fatalf("got: %v; want: %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRDAPRetry(t *testing.T) {
+	tests := []struct {
+		description string
+		failures    int32
+		retries     int
+		want        error
+	}{
+		{"no retries", 1, 0, ErrRDAPRateLimited},
+		{"recovered", 2, 2, nil},
+		{"exhausted", 3, 2, ErrRDAPRateLimited},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			var n atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if n.Add(1) <= tc.failures {
+					w.Header().Set("Retry-After", "0")
+					rdapErrorHandler(w, http.StatusTooManyRequests)
+					return
+				}
+				rdapHandler(w, r)
+			}))
+			defer server.Close()
+
+			client := NewRDAPClient(server.URL)
+			client.DefaultLimit = RateLimit{
+				Retries: tc.retries,
+				Backoff: time.Millisecond,
+			}
+
+			domain, err := client.Resolve(context.Background(), "example.com")
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("got: %v; want: %v", err, tc.want)
+			}
+			if err == nil && domain == nil {
+				t.Fatal("got: nil domain")
+			}
+		})
+	}
+}
@@ -3,85 +3,512 @@ package indeed
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	urlpkg "net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
 const RDAPBaseURL = "https://rdap.org/"
 
+const rdapMediaType = "application/rdap+json"
+
+// rdapCacheSize is the number of URLs whose responses are kept for
+// conditional requests.
+const rdapCacheSize = 1000
+
+var (
+	ErrRDAPRateLimited = errors.New("rate limited")
+	ErrRDAPForbidden   = errors.New("forbidden")
+	ErrRDAPServer      = errors.New("server error")
+	ErrRDAPMalformed   = errors.New("malformed response")
+	errRDAPStatus      = errors.New("unexpected response status")
+)
+
+type rdapEntity struct {
+	Handle    string          `json:"handle"`
+	Roles     []string        `json:"roles"`
+	VCard     json.RawMessage `json:"vcardArray"`
+	PublicIDs []struct {
+		Type       string `json:"type"`
+		Identifier string `json:"identifier"`
+	} `json:"publicIds"`
+	Entities []rdapEntity `json:"entities"`
+}
+
+type rdapNotice struct {
+	Title       string   `json:"title"`
+	Type        string   `json:"type"`
+	Description []string `json:"description"`
+	Links       []struct {
+		Href string `json:"href"`
+	} `json:"links"`
+}
+
+func (n *rdapNotice) notice() Notice {
+	notice := Notice{
+		Title:       n.Title,
+		Type:        n.Type,
+		Description: n.Description,
+	}
+	if len(n.Links) > 0 {
+		notice.Link = n.Links[0].Href
+	}
+	return notice
+}
+
+type rdapObject struct {
+	Name        string   `json:"ldhName"`
+	UnicodeName string   `json:"unicodeName"`
+	Status      []string `json:"status"`
+	Nameservers []struct {
+		Name string `json:"ldhName"`
+	} `json:"nameservers"`
+	Entities  []rdapEntity `json:"entities"`
+	SecureDNS *struct {
+		Signed bool `json:"delegationSigned"`
+		DSData []struct {
+			KeyTag     int    `json:"keyTag"`
+			Algorithm  int    `json:"algorithm"`
+			DigestType int    `json:"digestType"`
+			Digest     string `json:"digest"`
+		} `json:"dsData"`
+	} `json:"secureDNS"`
+	Links []struct {
+		Rel  string `json:"rel"`
+		Href string `json:"href"`
+		Type string `json:"type"`
+	} `json:"links"`
+	Events []struct {
+		Action string    `json:"eventAction"`
+		Actor  string    `json:"eventActor"`
+		Date   time.Time `json:"eventDate"`
+	} `json:"events"`
+	Redacted []rdapRedaction `json:"redacted"`
+	Notices  []rdapNotice    `json:"notices"`
+	Remarks  []rdapNotice    `json:"remarks"`
+}
+
 type RDAPClient struct {
-	BaseURL string
-	client  *http.Client
+	BaseURL      string
+	Bootstrap    *Bootstrap
+	Referrals    int
+	LinkTemplate string
+	Limits       map[string]RateLimit
+	DefaultLimit RateLimit
+	client       *http.Client
+
+	mu      sync.Mutex
+	buckets tokenBuckets
+	entries lruCache[*rdapCacheEntry]
+}
+
+type rdapCacheEntry struct {
+	etag         string
+	lastModified string
+	domain       *Domain
+	related      []string
 }
 
-func NewRDAPClient(baseURL string) *RDAPClient {
+func NewRDAPClient(baseURL string, opts ...RDAPOption) *RDAPClient {
+	var o rdapOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
 	return &RDAPClient{
 		BaseURL: baseURL,
-		client:  &http.Client{},
+		client:  o.httpClient(),
 	}
 }
 
+func (c *RDAPClient) LoadBootstrap(ctx context.Context, src string) error {
+	b, err := loadBootstrap(ctx, c.client, src)
+	if err != nil {
+		return err
+	}
+	c.Bootstrap = b
+	return nil
+}
+
 func (c *RDAPClient) Resolve(ctx context.Context, name string) (*Domain, error) {
-	url, err := urlpkg.JoinPath(c.BaseURL, "domain", name)
+	return c.ResolveObject(ctx, ClassDomain, name)
+}
+
+func (c *RDAPClient) Resolver(class ObjectClass) Resolver {
+	return &objectResolver{
+		c:     c,
+		class: class,
+	}
+}
+
+func (c *RDAPClient) ResolveObject(ctx context.Context, class ObjectClass, key string) (*Domain, error) {
+	key, err := canonicalKey(class, key)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	baseURL := c.BaseURL
+	if class == ClassDomain || class == ClassNameserver {
+		baseURL = c.baseURL(key)
+	}
+
+	url, err := urlpkg.JoinPath(baseURL, string(class), pathKey(class, key))
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := c.client.Do(req)
-	if err != nil {
+	domain, related, err := c.lookup(ctx, url)
+	if err != nil || domain == nil {
 		return nil, err
 	}
+
+	seen := map[string]bool{url: true}
+	c.follow(ctx, domain, related, c.Referrals, seen)
+
+	domain.Class = class
+	if domain.Name == "" {
+		domain.Name = key
+	}
+
+	if c.LinkTemplate != "" && class == ClassDomain {
+		domain.Link = strings.ReplaceAll(c.LinkTemplate, "{name}", urlpkg.PathEscape(key))
+	}
+
+	return domain, nil
+}
+
+func (c *RDAPClient) lookup(ctx context.Context, url string) (*Domain, []string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cached := c.cached(url)
+	if cached != nil {
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	res, err := c.do(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
 	defer res.Body.Close()
 
+	if res.StatusCode == http.StatusNotModified && cached != nil {
+		return cached.domain.clone(), cached.related, nil
+	}
+
 	if res.StatusCode != http.StatusOK {
 		if res.StatusCode == http.StatusNotFound {
-			return nil, nil
+			return nil, nil, nil
+		}
+		return nil, nil, c.unmarshalError(url, res)
+	}
+
+	domain, related, err := c.unmarshal(res.Body, res.Request.URL.String())
+	if err != nil {
+		return nil, nil, &RDAPError{
+			URL:        url,
+			StatusCode: res.StatusCode,
+			Status:     res.Status,
+			err:        ErrRDAPMalformed,
+			cause:      err,
+		}
+	}
+
+	if etag, lastModified := res.Header.Get("ETag"), res.Header.Get("Last-Modified"); etag != "" || lastModified != "" {
+		c.cache(url, &rdapCacheEntry{
+			etag:         etag,
+			lastModified: lastModified,
+			domain:       domain.clone(),
+			related:      related,
+		})
+	}
+
+	return domain, related, nil
+}
+
+func (c *RDAPClient) cached(url string) *rdapCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, _ := c.entries.get(url)
+	return entry
+}
+
+func (c *RDAPClient) cache(url string, entry *rdapCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries.put(url, entry, rdapCacheSize)
+}
+
+func (c *RDAPClient) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	limit := c.limit(host)
+
+	for attempt := 0; ; attempt++ {
+		if err := c.bucket(host, limit).wait(ctx); err != nil {
+			return nil, err
+		}
+
+		res, err := c.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if attempt >= limit.Retries {
+			return res, nil
+		}
+		if res.StatusCode != http.StatusTooManyRequests && res.StatusCode != http.StatusServiceUnavailable {
+			return res, nil
+		}
+
+		delay := retryAfter(res.Header, time.Now())
+		if delay == 0 {
+			delay = limit.backoff(attempt)
+		}
+		if limit.MaxWait > 0 && delay > limit.MaxWait {
+			return res, nil
+		}
+		res.Body.Close()
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
 		}
-		return nil, fmt.Errorf("unexpected response status %q", res.Status)
 	}
+}
+
+func (c *RDAPClient) limit(host string) RateLimit {
+	if limit, ok := c.Limits[host]; ok {
+		return limit
+	}
+	return c.DefaultLimit
+}
 
-	return c.unmarshal(res.Body)
+func (c *RDAPClient) bucket(host string, limit RateLimit) *tokenBucket {
+	return c.buckets.get(host, limit)
 }
 
-func (c *RDAPClient) unmarshal(r io.Reader) (*Domain, error) {
+func (c *RDAPClient) unmarshalError(url string, res *http.Response) error {
+	e := &RDAPError{
+		URL:        url,
+		StatusCode: res.StatusCode,
+		Status:     res.Status,
+		RetryAfter: retryAfter(res.Header, time.Now()),
+	}
+
+	switch {
+	case res.StatusCode == http.StatusTooManyRequests:
+		e.err = ErrRDAPRateLimited
+	case res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden:
+		e.err = ErrRDAPForbidden
+	case res.StatusCode >= http.StatusInternalServerError:
+		e.err = ErrRDAPServer
+	default:
+		e.err = errRDAPStatus
+	}
+
 	var body struct {
-		Name   string `json:"ldhName"`
-		Events []struct {
-			Action string    `json:"eventAction"`
-			Actor  string    `json:"eventActor"`
-			Date   time.Time `json:"eventDate"`
-		} `json:"events"`
+		ErrorCode   int      `json:"errorCode"`
+		Title       string   `json:"title"`
+		Description []string `json:"description"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err == nil {
+		e.ErrorCode = body.ErrorCode
+		e.Title = body.Title
+		e.Description = body.Description
+	}
+
+	return e
+}
+
+// follow merges the events of related registrar objects into domain.
+// Referrals are best effort: a failing registrar server leaves the
+// registry response as it is.
+func (c *RDAPClient) follow(ctx context.Context, domain *Domain, urls []string, depth int, seen map[string]bool) {
+	if depth <= 0 {
+		return
+	}
+
+	for _, url := range urls {
+		if seen[url] {
+			continue
+		}
+		seen[url] = true
+
+		referral, related, err := c.lookup(ctx, url)
+		if err != nil || referral == nil {
+			continue
+		}
+
+		for _, event := range referral.Events {
+			if domain.hasEvent(event.Action, event.Date) {
+				continue
+			}
+			event.Source = url
+			domain.Events = append(domain.Events, event)
+		}
+
+		c.follow(ctx, domain, related, depth-1, seen)
+	}
+}
+
+// unmarshalEntities adds the registrar and contacts of entities to
+// domain. An entity with a malformed jCard keeps only its handle, roles
+// and public IDs.
+func (c *RDAPClient) unmarshalEntities(entities []rdapEntity, domain *Domain) {
+	for _, e := range entities {
+		card, err := parseJCard(e.VCard)
+		if err != nil {
+			card = nil
+		}
+
+		entity := Entity{
+			Handle: e.Handle,
+			Roles:  e.Roles,
+			Name:   card.get("fn"),
+			Org:    card.get("org"),
+			Email:  card.get("email"),
+			Phone:  strings.TrimPrefix(card.get("tel"), "tel:"),
+		}
+		for _, id := range e.PublicIDs {
+			if id.Type == "IANA Registrar ID" {
+				entity.IANAID = id.Identifier
+			}
+		}
+		domain.redactPlaceholders(&entity)
+
+		if entity.HasRole("registrar") {
+			domain.Registrar = &entity
+		} else if entity.Name != "" || entity.Org != "" || entity.Email != "" || entity.Phone != "" {
+			domain.Contacts = append(domain.Contacts, entity)
+		}
+
+		c.unmarshalEntities(e.Entities, domain)
 	}
+}
+
+func (c *RDAPClient) baseURL(name string) string {
+	if c.Bootstrap != nil {
+		if url, ok := c.Bootstrap.Lookup(name); ok {
+			return url
+		}
+	}
+	return c.BaseURL
+}
+
+func (c *RDAPClient) unmarshal(r io.Reader, url string) (*Domain, []string, error) {
+	var body rdapObject
 	if err := json.NewDecoder(r).Decode(&body); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	return c.unmarshalObject(&body, url)
+}
+
+func (c *RDAPClient) unmarshalObject(body *rdapObject, url string) (*Domain, []string, error) {
 	domain := Domain{
-		Name:   body.Name,
-		Events: make([]Event, len(body.Events)),
+		Name:        body.Name,
+		UnicodeName: body.UnicodeName,
+		Status:      body.Status,
+		Events:      make([]Event, len(body.Events)),
+	}
+
+	for _, ns := range body.Nameservers {
+		domain.Nameservers = append(domain.Nameservers, ns.Name)
+	}
+
+	if body.SecureDNS != nil {
+		domain.DNSSEC = &DNSSEC{
+			Signed: body.SecureDNS.Signed,
+		}
+		for _, ds := range body.SecureDNS.DSData {
+			domain.DNSSEC.DS = append(domain.DNSSEC.DS, DSRecord{
+				KeyTag:     ds.KeyTag,
+				Algorithm:  ds.Algorithm,
+				DigestType: ds.DigestType,
+				Digest:     ds.Digest,
+			})
+		}
+	}
+
+	for _, r := range body.Redacted {
+		domain.redact(r.redaction())
+	}
+
+	for _, n := range body.Notices {
+		domain.Notices = append(domain.Notices, n.notice())
+	}
+	for _, n := range body.Remarks {
+		domain.Remarks = append(domain.Remarks, n.notice())
 	}
 
+	c.unmarshalEntities(body.Entities, &domain)
+
 	for i, event := range body.Events {
 		domain.Events[i] = Event{
-			Action: event.Action,
+			Action: ParseEventAction(event.Action),
 			Actor:  event.Actor,
 			Date:   event.Date,
 		}
+		if domain.Events[i].Action == ActionOther {
+			domain.Events[i].Detail = event.Action
+		}
 	}
 
-	link, err := urlpkg.JoinPath(RDAPBaseURL, "domain", domain.Name)
-	if err != nil {
-		return nil, err
+	domain.Link = url
+
+	var related []string
+	for _, link := range body.Links {
+		switch {
+		case link.Href == "":
+		case link.Rel == "self":
+			domain.Link = link.Href
+		case link.Rel == "related" && link.Type == rdapMediaType:
+			related = append(related, link.Href)
+		}
+	}
+
+	return &domain, related, nil
+}
+
+type RDAPError struct {
+	URL         string
+	StatusCode  int
+	Status      string
+	ErrorCode   int
+	Title       string
+	Description []string
+	RetryAfter  time.Duration
+	err         error
+	cause       error
+}
+
+func (e *RDAPError) Error() string {
+	msg := fmt.Sprintf("RDAP %s: %v (%s)", e.URL, e.err, e.Status)
+	if e.cause != nil {
+		msg = fmt.Sprintf("%s: %v", msg, e.cause)
 	}
-	domain.Link = link
+	if e.Title != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Title)
+	}
+	if len(e.Description) > 0 {
+		msg = fmt.Sprintf("%s: %s", msg, strings.Join(e.Description, " "))
+	}
+	return msg
+}
 
-	return &domain, nil
+func (e *RDAPError) Unwrap() []error {
+	if e.cause != nil {
+		return []error{e.err, e.cause}
+	}
+	return []error{e.err}
 }
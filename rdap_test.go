@@ -2,12 +2,15 @@ package indeed
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -22,8 +25,35 @@ func TestRDAP(t *testing.T) {
 			"ok",
 			"example.com",
 			&Domain{
-				Name: "EXAMPLE.COM",
-				Link: "https://rdap.org/domain/EXAMPLE.COM",
+				Class: ClassDomain,
+				Name:  "EXAMPLE.COM",
+				Link:  "https://rdap.verisign.com/com/v1/domain/EXAMPLE.COM",
+				Status: []string{
+					"client delete prohibited",
+					"client transfer prohibited",
+					"client update prohibited",
+				},
+				Nameservers: []string{
+					"A.IANA-SERVERS.NET",
+					"B.IANA-SERVERS.NET",
+				},
+				Registrar: &Entity{
+					Handle: "376",
+					Roles:  []string{"registrar"},
+					Name:   "RESERVED-Internet Assigned Numbers Authority",
+					IANAID: "376",
+				},
+				DNSSEC: &DNSSEC{
+					Signed: true,
+					DS: []DSRecord{
+						{
+							KeyTag:     370,
+							Algorithm:  13,
+							DigestType: 2,
+							Digest:     "BE74359954660069D5C63D200C39F5603827D7DD02B56F120EE9F3A86764247C",
+						},
+					},
+				},
 				Events: []Event{
 					{
 						Action: "registration",
@@ -42,6 +72,23 @@ func TestRDAP(t *testing.T) {
 						Date:   time.Date(2023, 8, 19, 8, 16, 0, 0, time.UTC),
 					},
 				},
+				Notices: []Notice{
+					{
+						Title:       "Terms of Use",
+						Description: []string{"Service subject to Terms of Use."},
+						Link:        "https://www.verisign.com/domain-names/registration-data-access-protocol/terms-service/index.xhtml",
+					},
+					{
+						Title:       "Status Codes",
+						Description: []string{"For more information on domain status codes, please visit https://icann.org/epp"},
+						Link:        "https://icann.org/epp",
+					},
+					{
+						Title:       "RDDS Inaccuracy Complaint Form",
+						Description: []string{"URL of the ICANN RDDS Inaccuracy Complaint Form: https://icann.org/wicf"},
+						Link:        "https://icann.org/wicf",
+					},
+				},
 			},
 		},
 		{
@@ -70,15 +117,432 @@ func TestRDAP(t *testing.T) {
 	}
 }
 
+func TestRDAPObject(t *testing.T) {
+	tests := []struct {
+		description string
+		class       ObjectClass
+		key         string
+		want        *Domain
+	}{
+		{
+			"ip network",
+			ClassIP,
+			"192.0.2.1/24",
+			&Domain{
+				Class:  ClassIP,
+				Name:   "192.0.2.0/24",
+				Link:   "https://rdap.arin.net/registry/ip/192.0.2.0",
+				Status: []string{"active"},
+				Contacts: []Entity{
+					{
+						Handle: "IANA",
+						Roles:  []string{"registrant"},
+						Name:   "Internet Assigned Numbers Authority",
+					},
+					{
+						Handle: "IANA-IP-ARIN",
+						Roles:  []string{"abuse"},
+						Name:   "ICANN",
+						Email:  "abuse@iana.org",
+						Phone:  "+1-310-301-5820",
+					},
+				},
+				Events: []Event{
+					{
+						Action: ActionLastChanged,
+						Date:   time.Date(2013, 8, 30, 14, 20, 14, 0, time.UTC),
+					},
+					{
+						Action: ActionRegistration,
+						Date:   time.Date(2010, 1, 29, 5, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+		},
+		{
+			"autnum",
+			ClassAutnum,
+			"as64496",
+			&Domain{
+				Class:  ClassAutnum,
+				Name:   "AS64496",
+				Link:   "https://rdap.arin.net/registry/autnum/64496",
+				Status: []string{"active"},
+				Events: []Event{
+					{
+						Action: ActionLastChanged,
+						Date:   time.Date(2011, 2, 1, 5, 0, 0, 0, time.UTC),
+					},
+					{
+						Action: ActionRegistration,
+						Date:   time.Date(2008, 2, 21, 5, 0, 0, 0, time.UTC),
+					},
+					{
+						Action: ActionTransfer,
+						Date:   time.Date(2010, 6, 14, 16, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+		},
+		{
+			"not found",
+			ClassAutnum,
+			"AS64497",
+			nil,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(rdapHandler))
+			defer server.Close()
+
+			client := NewRDAPClient(server.URL)
+
+			got, err := client.Resolver(tc.class).Resolve(context.Background(), tc.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != nil {
+				for i := range got.Events {
+					got.Events[i].Date = got.Events[i].Date.UTC()
+				}
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got: %v; want: %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRDAPMalformedJCard(t *testing.T) {
+	body := `{
+		"ldhName": "EXAMPLE.COM",
+		"entities": [
+			{
+				"roles": ["registrar"],
+				"vcardArray": ["vcard"],
+				"publicIds": [{"type": "IANA Registrar ID", "identifier": "376"}]
+			},
+			{
+				"roles": ["registrant"],
+				"vcardArray": ["vcard", [["fn", {}, "text", "Example Inc."]]]
+			}
+		]
+	}`
+
+	c := NewRDAPClient(RDAPBaseURL)
+	got, _, err := c.unmarshal(strings.NewReader(body), "https://rdap.example/domain/example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantRegistrar := &Entity{Roles: []string{"registrar"}, IANAID: "376"}
+	if !reflect.DeepEqual(got.Registrar, wantRegistrar) {
+		t.Fatalf("got: %v; want: %v", got.Registrar, wantRegistrar)
+	}
+	wantContacts := []Entity{{Roles: []string{"registrant"}, Name: "Example Inc."}}
+	if !reflect.DeepEqual(got.Contacts, wantContacts) {
+		t.Fatalf("got: %v; want: %v", got.Contacts, wantContacts)
+	}
+}
+
+func TestRDAPReferrals(t *testing.T) {
+	registry := []Event{
+		{
+			Action: "registration",
+			Date:   time.Date(1995, 8, 14, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			Action: "expiration",
+			Date:   time.Date(2024, 8, 13, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			Action: "last changed",
+			Date:   time.Date(2023, 8, 14, 7, 1, 38, 0, time.UTC),
+		},
+		{
+			Action: "last update of RDAP database",
+			Date:   time.Date(2023, 8, 19, 8, 16, 0, 0, time.UTC),
+		},
+	}
+	tests := []struct {
+		description string
+		referrals   int
+		status      int
+		want        func(registrar string) []Event
+	}{
+		{
+			"disabled",
+			0,
+			http.StatusOK,
+			func(string) []Event {
+				return registry
+			},
+		},
+		{
+			"registrar error",
+			1,
+			http.StatusInternalServerError,
+			func(string) []Event {
+				return registry
+			},
+		},
+		{
+			"registrar",
+			1,
+			http.StatusOK,
+			func(registrar string) []Event {
+				return append(registry[:len(registry):len(registry)],
+					Event{
+						Action: "registrar expiration",
+						Date:   time.Date(2024, 8, 13, 4, 0, 0, 0, time.UTC),
+						Source: registrar,
+					},
+					Event{
+						Action: "transfer",
+						Date:   time.Date(2021, 3, 2, 12, 30, 0, 0, time.UTC),
+						Source: registrar,
+					},
+					Event{
+						Action: "last update of RDAP database",
+						Date:   time.Date(2023, 8, 19, 9, 0, 0, 0, time.UTC),
+						Source: registrar,
+					},
+				)
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/domain/example.com":
+					rdapRelatedHandler(w, "testdata/rdap-example-com.json", server.URL+"/registrar/domain/example.com")
+				case "/registrar/domain/example.com":
+					if tc.status != http.StatusOK {
+						rdapErrorHandler(w, tc.status)
+						return
+					}
+					rdapRelatedHandler(w, "testdata/rdap-registrar-example-com.json", server.URL+"/domain/example.com")
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			client := NewRDAPClient(server.URL)
+			client.Referrals = tc.referrals
+
+			got, err := client.Resolve(context.Background(), "example.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := tc.want(server.URL + "/registrar/domain/example.com")
+			if !reflect.DeepEqual(got.Events, want) {
+				t.Fatalf("got: %v; want: %v", got.Events, want)
+			}
+		})
+	}
+}
+
+func TestRDAPLink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/domain/example.net" {
+			fmt.Fprint(w, `{"objectClassName": "domain", "ldhName": "EXAMPLE.NET"}`)
+			return
+		}
+		rdapHandler(w, r)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		description string
+		name        string
+		template    string
+		want        string
+	}{
+		{"self", "example.com", "", "https://rdap.verisign.com/com/v1/domain/EXAMPLE.COM"},
+		{"fallback", "example.net", "", server.URL + "/domain/example.net"},
+		{"template", "example.com", "https://lookup.example/?name={name}", "https://lookup.example/?name=example.com"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			client := NewRDAPClient(server.URL)
+			client.LinkTemplate = tc.template
+
+			got, err := client.Resolve(context.Background(), tc.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got.Link != tc.want {
+				t.Fatalf("got: %q; want: %q", got.Link, tc.want)
+			}
+		})
+	}
+}
+
+func TestRDAPConditional(t *testing.T) {
+	tests := []struct {
+		description string
+		header      string
+		value       string
+		condition   string
+	}{
+		{"etag", "ETag", `"8c0e7bce"`, "If-None-Match"},
+		{"last modified", "Last-Modified", "Sat, 19 Aug 2023 08:16:00 GMT", "If-Modified-Since"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			var full, notModified int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(tc.header, tc.value)
+				if r.Header.Get(tc.condition) == tc.value {
+					notModified++
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				full++
+				rdapHandler(w, r)
+			}))
+			defer server.Close()
+
+			client := NewRDAPClient(server.URL)
+
+			first, err := client.Resolve(context.Background(), "example.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+			second, err := client.Resolve(context.Background(), "example.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if full != 1 || notModified != 1 {
+				t.Fatalf("got: %d full, %d not modified; want: 1, 1", full, notModified)
+			}
+			if !reflect.DeepEqual(first, second) {
+				t.Fatalf("got: %v; want: %v", second, first)
+			}
+
+			// Modifying a result must not change the cached domain.
+			second.Status[0] = "modified"
+			second.Nameservers[0] = "modified"
+			second.Events[0].Detail = "modified"
+			third, err := client.Resolve(context.Background(), "example.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(first, third) {
+				t.Fatalf("got: %v; want: %v", third, first)
+			}
+		})
+	}
+}
+
+func rdapRelatedHandler(w http.ResponseWriter, name string, related string) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(b, &body); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	links, _ := body["links"].([]any)
+	body["links"] = append(links, map[string]any{
+		"rel":  "related",
+		"href": related,
+		"type": "application/rdap+json",
+	})
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func TestRDAPError(t *testing.T) {
+	tests := []struct {
+		description string
+		name        string
+		want        error
+		status      int
+		title       string
+	}{
+		{"rate limited", "429.com", ErrRDAPRateLimited, http.StatusTooManyRequests, "Too Many Requests"},
+		{"forbidden", "403.com", ErrRDAPForbidden, http.StatusForbidden, "Forbidden"},
+		{"server error", "500.com", ErrRDAPServer, http.StatusInternalServerError, "Internal Server Error"},
+		{"malformed", "malformed.com", ErrRDAPMalformed, http.StatusOK, ""},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(rdapHandler))
+			defer server.Close()
+
+			client := NewRDAPClient(server.URL)
+
+			_, err := client.Resolve(context.Background(), tc.name)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("got: %v; want: %v", err, tc.want)
+			}
+
+			var rdapErr *RDAPError
+			if !errors.As(err, &rdapErr) {
+				t.Fatalf("got: %T; want: %T", err, rdapErr)
+			}
+			if rdapErr.StatusCode != tc.status || rdapErr.Title != tc.title {
+				t.Fatalf("got: %d %q; want: %d %q", rdapErr.StatusCode, rdapErr.Title, tc.status, tc.title)
+			}
+		})
+	}
+}
+
 func rdapHandler(w http.ResponseWriter, r *http.Request) {
 	var name string
 	switch r.URL.Path {
+	case "/domain/403.com":
+		rdapErrorHandler(w, http.StatusForbidden)
+		return
+	case "/domain/429.com":
+		rdapErrorHandler(w, http.StatusTooManyRequests)
+		return
+	case "/domain/500.com":
+		rdapErrorHandler(w, http.StatusInternalServerError)
+		return
+	case "/domain/malformed.com":
+		fmt.Fprint(w, "<html></html>")
+		return
 	case "/domain/example.com":
 		name = "testdata/rdap-example-com.json"
 	case "/domain/example.net":
 		name = "testdata/rdap-example-net.json"
 	case "/domain/example.org":
 		name = "testdata/rdap-example-org.json"
+	case "/domain/xn--bcher-kva.com":
+		name = "testdata/rdap-xn--bcher-kva-com.json"
+	case "/ip/192.0.2.0/24":
+		name = "testdata/rdap-ip-192-0-2-0.json"
+	case "/autnum/64496":
+		name = "testdata/rdap-autnum-64496.json"
+	case "/domains":
+		if r.URL.Query().Get("name") != "example*.com" {
+			http.Error(w, "no results", http.StatusNotFound)
+			return
+		}
+		name = "testdata/rdap-search-example.json"
 	default:
 		msg := fmt.Sprintf("unexpected path %q", r.URL.Path)
 		http.Error(w, msg, http.StatusNotFound)
@@ -97,3 +561,13 @@ func rdapHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func rdapErrorHandler(w http.ResponseWriter, status int) {
+	w.Header().Set("Content-Type", "application/rdap+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"errorCode":   status,
+		"title":       http.StatusText(status),
+		"description": []string{"Try again later."},
+	})
+}
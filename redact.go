@@ -0,0 +1,140 @@
+package indeed
+
+import "strings"
+
+type RedactionMethod string
+
+// Redaction methods (RFC 9537, section 3).
+const (
+	RedactionRemoval          RedactionMethod = "removal"
+	RedactionEmptyValue       RedactionMethod = "emptyValue"
+	RedactionPartialValue     RedactionMethod = "partialValue"
+	RedactionReplacementValue RedactionMethod = "replacementValue"
+)
+
+type Redaction struct {
+	Name   string
+	Method RedactionMethod
+	Reason string
+	Path   string
+}
+
+type rdapRedaction struct {
+	Name struct {
+		Type        string `json:"type"`
+		Description string `json:"description"`
+	} `json:"name"`
+	PrePath         string `json:"prePath"`
+	PostPath        string `json:"postPath"`
+	ReplacementPath string `json:"replacementPath"`
+	Method          string `json:"method"`
+	Reason          struct {
+		Type        string `json:"type"`
+		Description string `json:"description"`
+	} `json:"reason"`
+}
+
+func (r *rdapRedaction) redaction() Redaction {
+	redaction := Redaction{
+		Name:   firstNonEmpty(r.Name.Type, r.Name.Description),
+		Method: RedactionMethod(r.Method),
+		Reason: firstNonEmpty(r.Reason.Type, r.Reason.Description),
+		Path:   firstNonEmpty(r.PrePath, r.PostPath, r.ReplacementPath),
+	}
+	if redaction.Method == "" {
+		redaction.Method = RedactionRemoval
+	}
+	return redaction
+}
+
+// redactionPlaceholders are lowercase fragments of values that servers
+// put in place of redacted contact data.
+var redactionPlaceholders = []string{
+	"redacted",
+	"non-public data",
+	"not disclosed",
+	"data protected",
+	"withheld",
+	"please query the rdds service",
+	"request email form",
+}
+
+func isRedactionPlaceholder(value string) bool {
+	value = strings.ToLower(value)
+	for _, placeholder := range redactionPlaceholders {
+		if strings.Contains(value, placeholder) {
+			return true
+		}
+	}
+	return false
+}
+
+// contactRoles maps entity roles to the prefixes used by the RDAP JSON
+// values registry and WHOIS output, such as "Registrant" in "Registrant
+// Name".
+var contactRoles = map[string]string{
+	"registrant":     "Registrant",
+	"administrative": "Admin",
+	"technical":      "Tech",
+	"billing":        "Billing",
+	"abuse":          "Abuse",
+}
+
+// redactPlaceholders clears contact fields holding a placeholder and
+// records them as redacted.
+func (d *Domain) redactPlaceholders(entity *Entity) {
+	prefix := "Contact"
+	if len(entity.Roles) > 0 {
+		if p, ok := contactRoles[entity.Roles[0]]; ok {
+			prefix = p
+		}
+	}
+
+	for _, field := range []struct {
+		name  string
+		value *string
+	}{
+		{"Name", &entity.Name},
+		{"Organization", &entity.Org},
+		{"Email", &entity.Email},
+		{"Phone", &entity.Phone},
+	} {
+		if isRedactionPlaceholder(*field.value) {
+			d.redact(Redaction{
+				Name:   prefix + " " + field.name,
+				Method: RedactionEmptyValue,
+				Reason: *field.value,
+			})
+			*field.value = ""
+		}
+	}
+}
+
+// redact records a redaction unless one with the same name is already
+// recorded.
+func (d *Domain) redact(redaction Redaction) {
+	for _, r := range d.Redacted {
+		if strings.EqualFold(r.Name, redaction.Name) {
+			return
+		}
+	}
+	d.Redacted = append(d.Redacted, redaction)
+}
+
+func (d *Domain) IsRedacted(name string) bool {
+	for _, r := range d.Redacted {
+		if strings.EqualFold(r.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
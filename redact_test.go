@@ -0,0 +1,123 @@
+package indeed
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"testing"
+
+	"golang.org/x/sync/errgroup"
+)
+
+func TestRDAPRedacted(t *testing.T) {
+	f, err := os.Open("testdata/rdap-redacted-example.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	domain, _, err := NewRDAPClient(RDAPBaseURL).unmarshal(f, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redacted := []Redaction{
+		{
+			Name:   "Registry Registrant ID",
+			Method: RedactionRemoval,
+			Reason: "Server policy",
+			Path:   "$.entities[?(@.roles[0]=='registrant')].handle",
+		},
+		{
+			Name:   "Registrant Name",
+			Method: RedactionEmptyValue,
+			Reason: "Server policy",
+			Path:   "$.entities[?(@.roles[0]=='registrant')].vcardArray[1][?(@[0]=='fn')][3]",
+		},
+		{
+			Name:   "Registrant Phone",
+			Method: RedactionRemoval,
+			Path:   "$.entities[?(@.roles[0]=='registrant')].vcardArray[1][?(@[1].type=='voice')]",
+		},
+		{
+			Name:   "Registrant Email",
+			Method: RedactionEmptyValue,
+			Reason: "REDACTED FOR PRIVACY",
+		},
+	}
+	if !reflect.DeepEqual(domain.Redacted, redacted) {
+		t.Fatalf("got: %v; want: %v", domain.Redacted, redacted)
+	}
+
+	contacts := []Entity{{Roles: []string{"registrant"}, Org: "Example Inc."}}
+	if !reflect.DeepEqual(domain.Contacts, contacts) {
+		t.Fatalf("got: %v; want: %v", domain.Contacts, contacts)
+	}
+}
+
+func TestWHOISRedacted(t *testing.T) {
+	g, ctx := errgroup.WithContext(context.Background())
+	ch := make(chan net.Addr)
+
+	g.Go(func() error {
+		return whoisServer(ch)
+	})
+
+	g.Go(func() error {
+		addr := <-ch
+
+		c := &WHOISClient{
+			TLDs: map[string]WHOISServer{"org": {Addr: addr.String()}},
+		}
+		domain, err := c.Resolve(ctx, "example.org")
+		if err != nil {
+			return err
+		}
+
+		for _, name := range []string{"Registrant Name", "Registrant Email", "Admin Organization", "Tech Phone"} {
+			if !domain.IsRedacted(name) {
+				return fmt.Errorf("got: %v; want: %s redacted", domain.Redacted, name)
+			}
+		}
+		if domain.IsRedacted("Registrant Organization") {
+			return fmt.Errorf("got: %v; want: Registrant Organization not redacted", domain.Redacted)
+		}
+
+		contacts := []Entity{{Roles: []string{"registrant"}, Org: "ICANN"}}
+		if !reflect.DeepEqual(domain.Contacts, contacts) {
+			return fmt.Errorf("got: %v; want: %v", domain.Contacts, contacts)
+		}
+
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedactionPlaceholder(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"REDACTED FOR PRIVACY", true},
+		{"EMAIL REDACTED FOR PRIVACY", true},
+		{"[Non-Public Data]", true},
+		{"Data Protected", true},
+		{"Please query the RDDS service of the Registrar of Record identified in this output.", true},
+		{"ICANN", false},
+		{"hostmaster@example.com", false},
+		{"", false},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.value, func(t *testing.T) {
+			if got := isRedactionPlaceholder(tc.value); got != tc.want {
+				t.Fatalf("got: %t; want: %t", got, tc.want)
+			}
+		})
+	}
+}
@@ -3,8 +3,12 @@ package indeed
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"golang.org/x/net/idna"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -13,15 +17,166 @@ type Resolver interface {
 }
 
 type Domain struct {
+	Class       ObjectClass
+	Name        string
+	UnicodeName string
+	Link        string
+	Status      []string
+	Nameservers []string
+	Registrar   *Entity
+	Contacts    []Entity
+	DNSSEC      *DNSSEC
+	Events      []Event
+	Redacted    []Redaction
+	Notices     []Notice
+	Remarks     []Notice
+}
+
+type Entity struct {
+	Handle string
+	Roles  []string
 	Name   string
-	Link   string
-	Events []Event
+	Org    string
+	Email  string
+	Phone  string
+	IANAID string
+}
+
+func (e *Entity) HasRole(role string) bool {
+	for _, r := range e.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+type DNSSEC struct {
+	Signed bool
+	DS     []DSRecord
+}
+
+type DSRecord struct {
+	KeyTag     int
+	Algorithm  int
+	DigestType int
+	Digest     string
+}
+
+func (r DSRecord) String() string {
+	return fmt.Sprintf("%d %d %d %s", r.KeyTag, r.Algorithm, r.DigestType, strings.ToUpper(r.Digest))
 }
 
 type Event struct {
-	Action string
+	Action EventAction
 	Actor  string
 	Date   time.Time
+	Source string
+	Detail string
+}
+
+// Notice is an RDAP notice or remark (RFC 9083, section 4.3).
+type Notice struct {
+	Title       string
+	Type        string
+	Description []string
+	Link        string
+}
+
+func (n Notice) String() string {
+	desc := strings.Join(n.Description, " ")
+	switch {
+	case n.Title == "":
+		return desc
+	case desc == "":
+		return n.Title
+	default:
+		return fmt.Sprintf("%s: %s", n.Title, desc)
+	}
+}
+
+// clone returns a deep copy of d, which may be modified without
+// affecting d.
+func (d *Domain) clone() *Domain {
+	c := *d
+	c.Status = slices.Clone(d.Status)
+	c.Nameservers = slices.Clone(d.Nameservers)
+	if d.Registrar != nil {
+		registrar := d.Registrar.clone()
+		c.Registrar = &registrar
+	}
+	c.Contacts = slices.Clone(d.Contacts)
+	for i := range c.Contacts {
+		c.Contacts[i] = c.Contacts[i].clone()
+	}
+	if d.DNSSEC != nil {
+		dnssec := *d.DNSSEC
+		dnssec.DS = slices.Clone(d.DNSSEC.DS)
+		c.DNSSEC = &dnssec
+	}
+	c.Events = slices.Clone(d.Events)
+	c.Redacted = slices.Clone(d.Redacted)
+	c.Notices = cloneNotices(d.Notices)
+	c.Remarks = cloneNotices(d.Remarks)
+	return &c
+}
+
+func (e Entity) clone() Entity {
+	e.Roles = slices.Clone(e.Roles)
+	return e
+}
+
+func cloneNotices(notices []Notice) []Notice {
+	notices = slices.Clone(notices)
+	for i := range notices {
+		notices[i].Description = slices.Clone(notices[i].Description)
+	}
+	return notices
+}
+
+func (d *Domain) hasEvent(action EventAction, date time.Time) bool {
+	for _, event := range d.Events {
+		if event.Action == action && event.Date.Equal(date) {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalName normalizes a domain name to lowercase A-labels using the
+// UTS #46 lookup profile.
+func canonicalName(name string) (string, error) {
+	ascii, err := idna.Lookup.ToASCII(strings.TrimSuffix(name, "."))
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(ascii), nil
+}
+
+// canonicalTLD is canonicalName for a single label such as "de" or ".DE".
+func canonicalTLD(tld string) (string, error) {
+	tld, err := canonicalName(strings.TrimPrefix(tld, "."))
+	if err != nil {
+		return "", err
+	}
+	if tld == "" || strings.Contains(tld, ".") {
+		return "", errBadTLD
+	}
+	return tld, nil
+}
+
+func (d *Domain) DisplayName() string {
+	switch d.Class {
+	case ClassIP, ClassAutnum, ClassEntity:
+		return d.Name
+	}
+	if d.UnicodeName != "" {
+		return strings.ToLower(d.UnicodeName)
+	}
+	if name, err := idna.Display.ToUnicode(d.Name); err == nil {
+		return strings.ToLower(name)
+	}
+	return strings.ToLower(d.Name)
 }
 
 func ResolveDomains(ctx context.Context, resolver Resolver, names []string) ([]Domain, error) {
@@ -68,34 +223,45 @@ type multiResolver struct {
 	rr []Resolver
 }
 
+// MultiResolver asks all resolvers concurrently and returns the domain
+// found by the first of them, as soon as the resolvers before it have not
+// found it. Later resolvers are then cancelled. Errors are only returned
+// when no resolver finds the domain, so a failing fallback does not hide
+// a found domain.
 func MultiResolver(resolvers []Resolver) Resolver {
 	return &multiResolver{rr: resolvers}
 }
 
-func (r *multiResolver) Resolve(ctx context.Context, name string) (*Domain, error) {
-	g, ctx := errgroup.WithContext(ctx)
-	domains := make([]*Domain, len(r.rr))
+type multiResult struct {
+	domain *Domain
+	err    error
+}
 
-	for i := range r.rr {
-		i := i
-		g.Go(func() error {
-			var err error
-			domains[i], err = r.rr[i].Resolve(ctx, name)
-			return err
-		})
-	}
+func (r *multiResolver) Resolve(ctx context.Context, name string) (*Domain, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	if err := g.Wait(); err != nil {
-		return nil, err
+	results := make([]chan multiResult, len(r.rr))
+	for i, resolver := range r.rr {
+		ch := make(chan multiResult, 1)
+		results[i] = ch
+		go func(resolver Resolver) {
+			domain, err := resolver.Resolve(ctx, name)
+			ch <- multiResult{domain, err}
+		}(resolver)
 	}
 
-	for _, domain := range domains {
-		if domain != nil {
-			return domain, nil
+	var err error
+	for _, ch := range results {
+		result := <-ch
+		if result.domain != nil {
+			return result.domain, nil
+		}
+		if err == nil {
+			err = result.err
 		}
 	}
-
-	return nil, nil
+	return nil, err
 }
 
 type tryResolver struct {
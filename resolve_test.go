@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 )
 
 func TestMultiResolver(t *testing.T) {
@@ -32,6 +33,17 @@ func TestMultiResolver(t *testing.T) {
 			}),
 			one,
 		},
+		{
+			"found first",
+			"example.com",
+			MultiResolver([]Resolver{
+				mapResolver{
+					"example.com": one,
+				},
+				&errResolver{errors.New("unreachable")},
+			}),
+			one,
+		},
 		{
 			"fallback",
 			"example.com",
@@ -67,6 +79,41 @@ func TestMultiResolver(t *testing.T) {
 	}
 }
 
+func TestMultiResolverError(t *testing.T) {
+	want := errors.New("unreachable")
+	r := MultiResolver([]Resolver{
+		mapResolver{},
+		&errResolver{want},
+	})
+
+	if _, err := r.Resolve(context.Background(), "example.com"); err != want {
+		t.Fatalf("got: %v; want: %v", err, want)
+	}
+}
+
+func TestMultiResolverCancel(t *testing.T) {
+	want := &Domain{Name: "EXAMPLE.COM"}
+	hang := &hangResolver{done: make(chan struct{})}
+	r := MultiResolver([]Resolver{
+		mapResolver{"example.com": want},
+		hang,
+	})
+
+	got, err := r.Resolve(context.Background(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Fatalf("got: %v; want: %v", got, want)
+	}
+
+	select {
+	case <-hang.done:
+	case <-time.After(time.Second):
+		t.Fatal("fallback not cancelled")
+	}
+}
+
 func TestTryResolver(t *testing.T) {
 	one := errors.New("one")
 	two := errors.New("two")
@@ -106,6 +153,33 @@ func TestTryResolver(t *testing.T) {
 	}
 }
 
+func TestCanonicalName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		err  bool
+	}{
+		{"example.com", "example.com", false},
+		{"EXAMPLE.COM.", "example.com", false},
+		{"bücher.de", "xn--bcher-kva.de", false},
+		{"BÜCHER.de", "xn--bcher-kva.de", false},
+		{"xn--bcher-kva.de", "xn--bcher-kva.de", false},
+		{"exa mple.com", "", true},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := canonicalName(tc.name)
+			if (err != nil) != tc.err {
+				t.Fatalf("got: %v; want error: %t", err, tc.err)
+			}
+			if got != tc.want {
+				t.Fatalf("got: %q; want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
 type mapResolver map[string]*Domain
 
 func (r mapResolver) Resolve(ctx context.Context, name string) (*Domain, error) {
@@ -123,3 +197,14 @@ type errResolver struct {
 func (e *errResolver) Resolve(ctx context.Context, name string) (*Domain, error) {
 	return nil, e.err
 }
+
+// hangResolver blocks until its context is done.
+type hangResolver struct {
+	done chan struct{}
+}
+
+func (r *hangResolver) Resolve(ctx context.Context, name string) (*Domain, error) {
+	<-ctx.Done()
+	close(r.done)
+	return nil, ctx.Err()
+}
@@ -15,12 +15,13 @@ type RSSFeed struct {
 }
 
 type RSSItem struct {
-	Title       string  `xml:"title,omitempty"`
-	Link        string  `xml:"link,omitempty"`
-	Description string  `xml:"description,omitempty"`
-	Author      string  `xml:"author,omitempty"`
-	GUID        string  `xml:"guid,omitempty"`
-	PubDate     RSSTime `xml:"pubDate,omitempty"`
+	Title       string   `xml:"title,omitempty"`
+	Link        string   `xml:"link,omitempty"`
+	Description string   `xml:"description,omitempty"`
+	Author      string   `xml:"author,omitempty"`
+	Categories  []string `xml:"category"`
+	GUID        string   `xml:"guid,omitempty"`
+	PubDate     RSSTime  `xml:"pubDate,omitempty"`
 }
 
 type RSSTime struct {
@@ -0,0 +1,206 @@
+package indeed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/netip"
+	urlpkg "net/url"
+	"strings"
+	"time"
+)
+
+type SearchField string
+
+// Domain search fields (RFC 9082, section 3.2.1).
+const (
+	SearchName      SearchField = "name"
+	SearchNSLdhName SearchField = "nsLdhName"
+	SearchNSIP      SearchField = "nsIp"
+)
+
+var (
+	errBadField   = errors.New("unknown search field")
+	errBadPattern = errors.New("malformed search pattern")
+)
+
+func ParseSearchField(s string) (SearchField, error) {
+	for _, field := range []SearchField{SearchName, SearchNSLdhName, SearchNSIP} {
+		if strings.EqualFold(s, string(field)) {
+			return field, nil
+		}
+	}
+	return "", errBadField
+}
+
+// A Searcher searches the registry of a top-level domain for domains.
+// An empty tld means the registry of a name pattern, or the default
+// server for nameserver searches.
+type Searcher interface {
+	Search(ctx context.Context, field SearchField, pattern, tld string) ([]Domain, error)
+}
+
+func (c *RDAPClient) Search(ctx context.Context, field SearchField, pattern, tld string) ([]Domain, error) {
+	pattern, err := canonicalPattern(field, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if tld == "" && field == SearchName {
+		tld = pattern
+	}
+	baseURL := c.BaseURL
+	if tld != "" {
+		baseURL = c.baseURL(tld)
+	}
+
+	url, err := urlpkg.JoinPath(baseURL, "domains")
+	if err != nil {
+		return nil, err
+	}
+	url += "?" + urlpkg.Values{string(field): {pattern}}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		if res.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, c.unmarshalError(url, res)
+	}
+
+	var body struct {
+		Results []rdapObject `json:"domainSearchResults"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, &RDAPError{
+			URL:        url,
+			StatusCode: res.StatusCode,
+			Status:     res.Status,
+			err:        ErrRDAPMalformed,
+			cause:      err,
+		}
+	}
+
+	domains := make([]Domain, 0, len(body.Results))
+	for i := range body.Results {
+		domain, _, err := c.unmarshalObject(&body.Results[i], "")
+		if err != nil {
+			return nil, err
+		}
+		if domain.Link == "" {
+			domain.Link, _ = urlpkg.JoinPath(baseURL, "domain", domain.Name)
+		}
+		domain.Class = ClassDomain
+		domains = append(domains, *domain)
+	}
+
+	return domains, nil
+}
+
+// canonicalPattern normalizes a search pattern. Labels with a wildcard
+// must be LDH; other labels are converted to A-labels.
+func canonicalPattern(field SearchField, pattern string) (string, error) {
+	switch field {
+	case SearchNSIP:
+		addr, err := netip.ParseAddr(pattern)
+		if err != nil {
+			return "", errBadPattern
+		}
+		return addr.String(), nil
+	case SearchName, SearchNSLdhName:
+		labels := strings.Split(strings.TrimSuffix(pattern, "."), ".")
+		for i, label := range labels {
+			if !strings.Contains(label, "*") {
+				ascii, err := canonicalName(label)
+				if err != nil || ascii == "" {
+					return "", errBadPattern
+				}
+				labels[i] = ascii
+				continue
+			}
+			label = strings.ToLower(label)
+			for _, r := range label {
+				if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '*') {
+					return "", errBadPattern
+				}
+			}
+			labels[i] = label
+		}
+		return strings.Join(labels, "."), nil
+	default:
+		return "", errBadField
+	}
+}
+
+type trackSearcher struct {
+	s   Searcher
+	st  Store
+	now func() time.Time
+}
+
+// TrackSearcher remembers when each search result first appeared and adds
+// an ActionSearchMatched event for results that appeared after the first
+// search.
+func TrackSearcher(searcher Searcher, store Store) Searcher {
+	return &trackSearcher{
+		s:   searcher,
+		st:  store,
+		now: time.Now,
+	}
+}
+
+func (s *trackSearcher) Search(ctx context.Context, field SearchField, pattern, tld string) ([]Domain, error) {
+	domains, err := s.s.Search(ctx, field, pattern, tld)
+	if err != nil {
+		return nil, err
+	}
+
+	key := "search:" + string(field) + "=" + strings.ToLower(pattern)
+	if tld != "" {
+		key += "@" + strings.ToLower(tld)
+	}
+	prev, err := s.st.Load(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	matches := make(map[string]time.Time, len(domains))
+	for i := range domains {
+		name := strings.ToLower(domains[i].Name)
+
+		var date time.Time
+		if prev != nil {
+			var ok bool
+			if date, ok = prev.Matches[name]; !ok {
+				date = now
+			}
+		}
+		matches[name] = date
+
+		if !date.IsZero() {
+			domains[i].Events = append(domains[i].Events[:len(domains[i].Events):len(domains[i].Events)], Event{
+				Action: ActionSearchMatched,
+				Date:   date,
+				Detail: pattern,
+			})
+		}
+	}
+
+	if err := s.st.Save(ctx, key, &Snapshot{Matches: matches}); err != nil {
+		return nil, err
+	}
+
+	return domains, nil
+}
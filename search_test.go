@@ -0,0 +1,179 @@
+package indeed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(rdapHandler))
+	defer server.Close()
+
+	tests := []struct {
+		description string
+		field       SearchField
+		pattern     string
+		want        []string
+		err         error
+	}{
+		{"ok", SearchName, "Example*.COM", []string{"EXAMPLE.COM", "EXAMPLE-SHOP.COM"}, nil},
+		{"no results", SearchName, "nothing*.com", nil, nil},
+		{"bad pattern", SearchName, "exa_mple*.com", nil, errBadPattern},
+		{"bad address", SearchNSIP, "192.0.2", nil, errBadPattern},
+		{"bad field", "registrant", "example*.com", nil, errBadField},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			client := NewRDAPClient(server.URL)
+
+			domains, err := client.Search(context.Background(), tc.field, tc.pattern, "")
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("got: %v; want: %v", err, tc.err)
+			}
+
+			var got []string
+			for _, domain := range domains {
+				if domain.Class != ClassDomain {
+					t.Fatalf("got: %v; want: %v", domain.Class, ClassDomain)
+				}
+				got = append(got, domain.Name)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got: %v; want: %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSearchLink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(rdapHandler))
+	defer server.Close()
+
+	domains, err := NewRDAPClient(server.URL).Search(context.Background(), SearchName, "example*.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"https://rdap.verisign.com/com/v1/domain/EXAMPLE.COM",
+		server.URL + "/domain/EXAMPLE-SHOP.COM",
+	}
+	for i, domain := range domains {
+		if domain.Link != want[i] {
+			t.Fatalf("got: %v; want: %v", domain.Link, want[i])
+		}
+	}
+}
+
+func TestSearchRegistry(t *testing.T) {
+	var got string
+	handler := func(registry string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = registry
+			fmt.Fprint(w, `{"domainSearchResults": []}`)
+		})
+	}
+
+	base := httptest.NewServer(handler("base"))
+	defer base.Close()
+	com := httptest.NewServer(handler("com"))
+	defer com.Close()
+	net := httptest.NewServer(handler("net"))
+	defer net.Close()
+
+	bootstrap, err := ParseBootstrap(strings.NewReader(fmt.Sprintf(`{"services": [
+		[["com"], ["%s/"]],
+		[["net"], ["%s/"]]
+	]}`, com.URL, net.URL)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		description string
+		field       SearchField
+		pattern     string
+		tld         string
+		want        string
+	}{
+		{"name", SearchName, "example*.com", "", "com"},
+		{"nameserver", SearchNSLdhName, "ns1.example.net", "", "base"},
+		{"nameserver in tld", SearchNSLdhName, "ns1.example.net", "com", "com"},
+		{"address in tld", SearchNSIP, "192.0.2.1", "net", "net"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			client := NewRDAPClient(base.URL)
+			client.Bootstrap = bootstrap
+
+			got = ""
+			if _, err := client.Search(context.Background(), tc.field, tc.pattern, tc.tld); err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Fatalf("got: %q; want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTrackSearcher(t *testing.T) {
+	date := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	var s sliceSearcher
+	r := TrackSearcher(&s, NewMemoryStore()).(*trackSearcher)
+	r.now = func() time.Time {
+		return date
+	}
+
+	steps := []struct {
+		description string
+		names       []string
+		want        map[string]int
+	}{
+		{"first", []string{"EXAMPLE.COM"}, map[string]int{"EXAMPLE.COM": 0}},
+		{"added", []string{"EXAMPLE.COM", "EXAMPLE-SHOP.COM"}, map[string]int{"EXAMPLE.COM": 0, "EXAMPLE-SHOP.COM": 1}},
+		{"remembered", []string{"EXAMPLE-SHOP.COM", "EXAMPLE.COM"}, map[string]int{"EXAMPLE.COM": 0, "EXAMPLE-SHOP.COM": 1}},
+	}
+	for _, step := range steps {
+		s = nil
+		for _, name := range step.names {
+			s = append(s, Domain{Name: name})
+		}
+
+		domains, err := r.Search(context.Background(), SearchName, "example*.com", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := make(map[string]int)
+		for _, domain := range domains {
+			got[domain.Name] = len(domain.Events)
+			for _, event := range domain.Events {
+				if event.Action != ActionSearchMatched || !event.Date.Equal(date) || event.Detail != "example*.com" {
+					t.Fatalf("%s: got: %v; want: %v", step.description, event, ActionSearchMatched)
+				}
+			}
+		}
+		if !reflect.DeepEqual(got, step.want) {
+			t.Fatalf("%s: got: %v; want: %v", step.description, got, step.want)
+		}
+	}
+}
+
+type sliceSearcher []Domain
+
+func (s *sliceSearcher) Search(ctx context.Context, field SearchField, pattern, tld string) ([]Domain, error) {
+	domains := make([]Domain, len(*s))
+	copy(domains, *s)
+	return domains, nil
+}
@@ -0,0 +1,277 @@
+package indeed
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Snapshot struct {
+	Domain  Domain
+	Changes []Event
+	Matches map[string]time.Time
+}
+
+type Store interface {
+	Load(ctx context.Context, name string) (*Snapshot, error)
+	Save(ctx context.Context, name string, snapshot *Snapshot) error
+}
+
+const (
+	defaultMemoryStoreSize = 10000
+	// maxChanges is the number of most recent changes kept per name.
+	maxChanges = 100
+)
+
+// MemoryStore keeps the snapshots of up to Size names, forgetting the
+// least recently used name when full. A Size of zero means no limit.
+type MemoryStore struct {
+	Size int
+	mu   sync.Mutex
+	lru  lruCache[*Snapshot]
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		Size: defaultMemoryStoreSize,
+	}
+}
+
+func (s *MemoryStore) Load(ctx context.Context, name string) (*Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot, _ := s.lru.get(name)
+	return snapshot, nil
+}
+
+func (s *MemoryStore) Save(ctx context.Context, name string, snapshot *Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lru.put(name, snapshot, s.Size)
+	return nil
+}
+
+type trackResolver struct {
+	r     Resolver
+	s     Store
+	now   func() time.Time
+	locks keyedMutex
+}
+
+func TrackResolver(resolver Resolver, store Store) Resolver {
+	return &trackResolver{
+		r:   resolver,
+		s:   store,
+		now: time.Now,
+	}
+}
+
+func (r *trackResolver) Resolve(ctx context.Context, name string) (*Domain, error) {
+	domain, err := r.r.Resolve(ctx, name)
+	if err != nil || domain == nil {
+		return nil, err
+	}
+
+	// Concurrent resolves of a name must not both record a change
+	// against the same previous snapshot.
+	key := strings.ToLower(name)
+	unlock := r.locks.lock(key)
+	defer unlock()
+
+	prev, err := r.s.Load(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Event
+	if prev != nil {
+		changes = append(changes, prev.Changes...)
+		changes = append(changes, diff(&prev.Domain, domain, r.now().UTC())...)
+		if len(changes) > maxChanges {
+			changes = changes[len(changes)-maxChanges:]
+		}
+	}
+
+	if err := r.s.Save(ctx, key, &Snapshot{Domain: *domain, Changes: changes}); err != nil {
+		return nil, err
+	}
+
+	tracked := *domain
+	tracked.Events = append(domain.Events[:len(domain.Events):len(domain.Events)], changes...)
+
+	return &tracked, nil
+}
+
+// keyedMutex serialises work per key. The zero value is usable.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	mu      sync.Mutex
+	waiters int
+}
+
+func (m *keyedMutex) lock(key string) (unlock func()) {
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = make(map[string]*keyedLock)
+	}
+	l, ok := m.locks[key]
+	if !ok {
+		l = &keyedLock{}
+		m.locks[key] = l
+	}
+	l.waiters++
+	m.mu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if l.waiters--; l.waiters == 0 {
+			delete(m.locks, key)
+		}
+	}
+}
+
+func diff(prev, cur *Domain, date time.Time) []Event {
+	var events []Event
+
+	added, removed := diffStrings(prev.Status, cur.Status)
+	for _, status := range added {
+		events = append(events, Event{
+			Action: ActionStatusAdded,
+			Date:   date,
+			Detail: status,
+		})
+	}
+	for _, status := range removed {
+		events = append(events, Event{
+			Action: ActionStatusRemoved,
+			Date:   date,
+			Detail: status,
+		})
+	}
+
+	added, removed = diffStrings(noticeStrings(prev.Remarks), noticeStrings(cur.Remarks))
+	for _, remark := range added {
+		events = append(events, Event{
+			Action: ActionRemarkAdded,
+			Date:   date,
+			Detail: remark,
+		})
+	}
+	for _, remark := range removed {
+		events = append(events, Event{
+			Action: ActionRemarkRemoved,
+			Date:   date,
+			Detail: remark,
+		})
+	}
+
+	added, removed = diffStrings(prev.Nameservers, cur.Nameservers)
+	if len(added) > 0 || len(removed) > 0 {
+		events = append(events, Event{
+			Action: ActionNameserversChanged,
+			Date:   date,
+			Detail: strings.Join(cur.Nameservers, ", "),
+		})
+	}
+
+	if prev.Registrar != nil && cur.Registrar != nil && !sameEntity(prev.Registrar, cur.Registrar) {
+		detail := cur.Registrar.Name
+		if cur.Registrar.IANAID != "" {
+			detail = fmt.Sprintf("%s (IANA ID %s)", detail, cur.Registrar.IANAID)
+		}
+		events = append(events, Event{
+			Action: ActionRegistrarChanged,
+			Date:   date,
+			Detail: detail,
+		})
+	}
+
+	// Servers commonly omit DNSSEC data for unsigned domains.
+	prevDNSSEC, curDNSSEC := prev.DNSSEC, cur.DNSSEC
+	if prevDNSSEC == nil {
+		prevDNSSEC = &DNSSEC{}
+	}
+	if curDNSSEC == nil {
+		curDNSSEC = &DNSSEC{}
+	}
+	switch {
+	case prevDNSSEC.Signed && !curDNSSEC.Signed:
+		events = append(events, Event{
+			Action: ActionDelegationUnsigned,
+			Date:   date,
+		})
+	case !prevDNSSEC.Signed && curDNSSEC.Signed:
+		events = append(events, Event{
+			Action: ActionDelegationSigned,
+			Date:   date,
+		})
+	case curDNSSEC.Signed && len(prevDNSSEC.DS) > 0 && len(curDNSSEC.DS) > 0:
+		added, removed := diffStrings(dsStrings(prevDNSSEC.DS), dsStrings(curDNSSEC.DS))
+		if len(added) > 0 || len(removed) > 0 {
+			events = append(events, Event{
+				Action: ActionDSChanged,
+				Date:   date,
+				Detail: strings.Join(dsStrings(curDNSSEC.DS), ", "),
+			})
+		}
+	}
+
+	return events
+}
+
+func dsStrings(records []DSRecord) []string {
+	ss := make([]string, len(records))
+	for i, r := range records {
+		ss[i] = r.String()
+	}
+	return ss
+}
+
+func noticeStrings(notices []Notice) []string {
+	ss := make([]string, len(notices))
+	for i, n := range notices {
+		ss[i] = n.String()
+	}
+	return ss
+}
+
+func sameEntity(a, b *Entity) bool {
+	if a.IANAID != "" && b.IANAID != "" {
+		return a.IANAID == b.IANAID
+	}
+	return strings.EqualFold(a.Name, b.Name)
+}
+
+func diffStrings(prev, cur []string) (added, removed []string) {
+	seen := make(map[string]bool, len(prev))
+	for _, s := range prev {
+		seen[strings.ToLower(s)] = true
+	}
+	for _, s := range cur {
+		if !seen[strings.ToLower(s)] {
+			added = append(added, s)
+		}
+	}
+
+	seen = make(map[string]bool, len(cur))
+	for _, s := range cur {
+		seen[strings.ToLower(s)] = true
+	}
+	for _, s := range prev {
+		if !seen[strings.ToLower(s)] {
+			removed = append(removed, s)
+		}
+	}
+
+	return added, removed
+}
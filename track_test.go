@@ -0,0 +1,307 @@
+package indeed
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTrackResolver(t *testing.T) {
+	date := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+	registration := Event{
+		Action: "registration",
+		Date:   time.Date(1995, 8, 14, 4, 0, 0, 0, time.UTC),
+	}
+
+	m := mapResolver{
+		"example.com": &Domain{
+			Name:   "EXAMPLE.COM",
+			Status: []string{"client transfer prohibited"},
+			Events: []Event{registration},
+		},
+	}
+	r := TrackResolver(m, NewMemoryStore()).(*trackResolver)
+	r.now = func() time.Time {
+		return date
+	}
+
+	steps := []struct {
+		description string
+		status      []string
+		want        []Event
+	}{
+		{
+			"first",
+			[]string{"client transfer prohibited"},
+			[]Event{registration},
+		},
+		{
+			"unchanged",
+			[]string{"client transfer prohibited"},
+			[]Event{registration},
+		},
+		{
+			"changed",
+			[]string{"client hold"},
+			[]Event{
+				registration,
+				{Action: "status added", Date: date, Detail: "client hold"},
+				{Action: "status removed", Date: date, Detail: "client transfer prohibited"},
+			},
+		},
+		{
+			"remembered",
+			[]string{"client hold"},
+			[]Event{
+				registration,
+				{Action: "status added", Date: date, Detail: "client hold"},
+				{Action: "status removed", Date: date, Detail: "client transfer prohibited"},
+			},
+		},
+	}
+	for _, step := range steps {
+		m["example.com"] = &Domain{
+			Name:   "EXAMPLE.COM",
+			Status: step.status,
+			Events: []Event{registration},
+		}
+
+		got, err := r.Resolve(context.Background(), "example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(got.Events, step.want) {
+			t.Fatalf("%s: got: %v; want: %v", step.description, got.Events, step.want)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	date := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		description string
+		prev        Domain
+		cur         Domain
+		want        []Event
+	}{
+		{
+			"unchanged",
+			Domain{Nameservers: []string{"a.iana-servers.net", "b.iana-servers.net"}},
+			Domain{Nameservers: []string{"B.IANA-SERVERS.NET", "A.IANA-SERVERS.NET"}},
+			nil,
+		},
+		{
+			"nameservers changed",
+			Domain{Nameservers: []string{"a.iana-servers.net", "b.iana-servers.net"}},
+			Domain{Nameservers: []string{"ns1.example.net", "b.iana-servers.net"}},
+			[]Event{
+				{Action: "nameservers changed", Date: date, Detail: "ns1.example.net, b.iana-servers.net"},
+			},
+		},
+		{
+			"remark added",
+			Domain{},
+			Domain{Remarks: []Notice{{Title: "Dispute", Description: []string{"Subject to UDRP proceedings."}}}},
+			[]Event{
+				{Action: "remark added", Date: date, Detail: "Dispute: Subject to UDRP proceedings."},
+			},
+		},
+		{
+			"registrar renamed",
+			Domain{Registrar: &Entity{Name: "Example Registrar", IANAID: "9999"}},
+			Domain{Registrar: &Entity{Name: "Example Registrar, Inc.", IANAID: "9999"}},
+			nil,
+		},
+		{
+			"registrar changed",
+			Domain{Registrar: &Entity{Name: "Example Registrar", IANAID: "9999"}},
+			Domain{Registrar: &Entity{Name: "Other Registrar", IANAID: "1234"}},
+			[]Event{
+				{Action: "registrar changed", Date: date, Detail: "Other Registrar (IANA ID 1234)"},
+			},
+		},
+		{
+			"delegation unsigned",
+			Domain{DNSSEC: &DNSSEC{Signed: true, DS: []DSRecord{{370, 13, 2, "be74"}}}},
+			Domain{DNSSEC: &DNSSEC{Signed: false}},
+			[]Event{
+				{Action: "delegation unsigned", Date: date},
+			},
+		},
+		{
+			"delegation unsigned omitted",
+			Domain{DNSSEC: &DNSSEC{Signed: true, DS: []DSRecord{{370, 13, 2, "be74"}}}},
+			Domain{},
+			[]Event{
+				{Action: "delegation unsigned", Date: date},
+			},
+		},
+		{
+			"delegation signed omitted",
+			Domain{},
+			Domain{DNSSEC: &DNSSEC{Signed: true, DS: []DSRecord{{370, 13, 2, "be74"}}}},
+			[]Event{
+				{Action: "delegation signed", Date: date},
+			},
+		},
+		{
+			"DS digest case",
+			Domain{DNSSEC: &DNSSEC{Signed: true, DS: []DSRecord{{370, 13, 2, "be74"}}}},
+			Domain{DNSSEC: &DNSSEC{Signed: true, DS: []DSRecord{{370, 13, 2, "BE74"}}}},
+			nil,
+		},
+		{
+			"DS rollover",
+			Domain{DNSSEC: &DNSSEC{Signed: true, DS: []DSRecord{{370, 13, 2, "BE74"}}}},
+			Domain{DNSSEC: &DNSSEC{Signed: true, DS: []DSRecord{{2371, 13, 2, "71A4"}}}},
+			[]Event{
+				{Action: "DS records changed", Date: date, Detail: "2371 13 2 71A4"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			got := diff(&tc.prev, &tc.cur, date)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got: %v; want: %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	s.Size = 2
+
+	for _, name := range []string{"example.com", "example.net"} {
+		if err := s.Save(ctx, name, &Snapshot{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Loading example.com makes example.net the least recently used.
+	if _, err := s.Load(ctx, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(ctx, "example.org", &Snapshot{}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"example.com", true},
+		{"example.net", false},
+		{"example.org", true},
+	}
+	for _, tc := range tests {
+		got, err := s.Load(ctx, tc.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (got != nil) != tc.want {
+			t.Fatalf("%s: got: %v; want: %t", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestTrackResolverMaxChanges(t *testing.T) {
+	m := mapResolver{}
+	r := TrackResolver(m, NewMemoryStore()).(*trackResolver)
+
+	for i := 0; i < maxChanges+10; i++ {
+		status := "client hold"
+		if i%2 == 0 {
+			status = "client transfer prohibited"
+		}
+		m["example.com"] = &Domain{
+			Name:   "EXAMPLE.COM",
+			Status: []string{status},
+		}
+
+		if _, err := r.Resolve(context.Background(), "example.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := r.s.Load(context.Background(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Changes) != maxChanges {
+		t.Fatalf("got: %d; want: %d", len(got.Changes), maxChanges)
+	}
+}
+
+func TestTrackResolverConcurrent(t *testing.T) {
+	store := &slowStore{NewMemoryStore()}
+	m := mapResolver{
+		"example.com": &Domain{
+			Name:   "EXAMPLE.COM",
+			Status: []string{"client transfer prohibited"},
+		},
+	}
+	r := TrackResolver(m, store).(*trackResolver)
+	if _, err := r.Resolve(context.Background(), "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	date := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+	r.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		date = date.Add(time.Hour)
+		return date
+	}
+	m["example.com"] = &Domain{
+		Name:   "EXAMPLE.COM",
+		Status: []string{"client hold"},
+	}
+
+	var wg sync.WaitGroup
+	domains := make([]*Domain, 4)
+	for i := range domains {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			domains[i], _ = r.Resolve(context.Background(), "example.com")
+		}()
+	}
+	wg.Wait()
+
+	snapshot, err := store.Load(context.Background(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshot.Changes) != 2 {
+		t.Fatalf("got: %v; want: 2 changes", snapshot.Changes)
+	}
+
+	// Every resolve reports the one recorded change.
+	for _, domain := range domains {
+		if domain == nil {
+			t.Fatal("got: nil domain")
+		}
+		if !reflect.DeepEqual(domain.Events, snapshot.Changes) {
+			t.Fatalf("got: %v; want: %v", domain.Events, snapshot.Changes)
+		}
+	}
+}
+
+// slowStore widens the window between loading and saving a snapshot.
+type slowStore struct {
+	Store
+}
+
+func (s *slowStore) Load(ctx context.Context, name string) (*Snapshot, error) {
+	snapshot, err := s.Store.Load(ctx, name)
+	time.Sleep(10 * time.Millisecond)
+	return snapshot, err
+}
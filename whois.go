@@ -3,135 +3,357 @@ package indeed
 import (
 	"context"
 	"errors"
-	"io"
-	"net/textproto"
+	"fmt"
 	urlpkg "net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
+
+	"golang.org/x/sync/singleflight"
 )
 
-const whoisBaseURL = "https://www.whois.com/"
+const (
+	whoisBaseURL     = "https://www.whois.com/"
+	maxWHOISResponse = 1 << 20
+)
 
 var (
 	errNoServer = errors.New("no WHOIS server")
-	tldRE       = regexp.MustCompile(`\.[a-z]+$`)
 	updateRE    = regexp.MustCompile(`\S+Z\S*`)
 )
 
 type WHOISClient struct {
-	m func(string) string
+	// IANAServer is asked for the WHOIS server of top-level domains
+	// without an address in TLDs.
+	IANAServer string
+	// TLDs configures the WHOIS server of top-level domains.
+	TLDs map[string]WHOISServer
+	// Referrals is the maximum depth of "Registrar WHOIS Server"
+	// referrals to follow.
+	Referrals int
+	// Profiles maps WHOIS server hosts to the profile parsing their
+	// responses, overriding the built-in profiles.
+	Profiles map[string]WHOISProfile
+	// DialTimeout and ReadTimeout bound connecting to a server and
+	// reading its response. Zero means no timeout besides the context.
+	DialTimeout time.Duration
+	ReadTimeout time.Duration
+	// Messages maps WHOIS server hosts to messages recognized in addition
+	// to the built-in ones.
+	Messages map[string]WHOISMessages
+	// Charsets maps WHOIS server hosts to the charset of their responses,
+	// such as "iso-8859-1" or "euc-jp". Other responses are detected.
+	Charsets     map[string]string
+	Limits       map[string]RateLimit
+	DefaultLimit RateLimit
+	mu           sync.Mutex
+	servers      map[string]string
+	group        singleflight.Group
+	buckets      tokenBuckets
 }
 
 func NewWHOISClient() *WHOISClient {
 	return &WHOISClient{
-		m: func(name string) string {
-			switch tldRE.FindString(name) {
-			case ".io":
-				return "whois.nic.io:43"
-			default:
-				return ""
-			}
-		},
+		IANAServer:  IANAWHOISServer,
+		DialTimeout: 10 * time.Second,
+		ReadTimeout: 30 * time.Second,
 	}
 }
 
 func (c *WHOISClient) Resolve(ctx context.Context, name string) (*Domain, error) {
-	addr := c.m(name)
-	if addr == "" {
-		return nil, errNoServer
+	name, err := canonicalName(name)
+	if err != nil {
+		return nil, err
 	}
 
-	conn, err := textproto.Dial("tcp", addr)
+	addr, err := c.server(ctx, name)
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
 
-	id, err := conn.Cmd(name)
-	if err != nil {
+	domain, referral, err := c.query(ctx, addr, name, c.TLDs[whoisTLD(name)])
+	if err != nil || domain == nil {
 		return nil, err
 	}
 
-	conn.StartResponse(id)
-	defer conn.EndResponse(id)
+	seen := map[string]bool{addr: true}
+	c.follow(ctx, domain, name, referral, c.Referrals, seen)
 
-	return c.unmarshal(conn)
+	return domain, nil
 }
 
-func (c *WHOISClient) unmarshal(conn *textproto.Conn) (*Domain, error) {
-	domain := &Domain{
-		Events: make([]Event, 0),
+func (c *WHOISClient) profile(addr string) WHOISProfile {
+	host := whoisHost(addr)
+
+	if profile, ok := c.Profiles[host]; ok {
+		return profile
+	}
+	if profile, ok := whoisProfiles[host]; ok {
+		return profile
 	}
+	return WHOISProfileICANN
+}
 
-	for {
-		line, err := conn.ReadLine()
-		if err != nil {
-			if errors.Is(err, io.EOF) {
-				break
-			}
-			return nil, err
+// follow queries the registrar WHOIS server a registry referred to and
+// merges its events, contacts and redactions into domain. Referrals are
+// best effort: a failing registrar server leaves domain as it is.
+func (c *WHOISClient) follow(ctx context.Context, domain *Domain, name, referral string, depth int, seen map[string]bool) {
+	if depth <= 0 || referral == "" {
+		return
+	}
+
+	host := referral
+	if u, err := urlpkg.Parse(referral); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	addr := whoisAddr(host)
+	if seen[addr] {
+		return
+	}
+	seen[addr] = true
+
+	registrar, referral, err := c.query(ctx, addr, name, WHOISServer{})
+	if err != nil || registrar == nil {
+		return
+	}
+
+	source := strings.TrimSuffix(host, ":43")
+	for _, event := range registrar.Events {
+		if domain.hasEvent(event.Action, event.Date) {
+			continue
 		}
+		event.Source = source
+		domain.Events = append(domain.Events, event)
+	}
 
-		before, after, found := c.cutTrim(line)
-		if !found {
-			break
+	for _, contact := range registrar.Contacts {
+		if len(contact.Roles) == 0 || domain.hasContact(contact.Roles[0]) {
+			continue
 		}
+		domain.Contacts = append(domain.Contacts, contact)
+	}
+
+	for _, r := range registrar.Redacted {
+		domain.redact(r)
+	}
+
+	c.follow(ctx, domain, name, referral, depth-1, seen)
+}
+
+func (c *WHOISClient) unmarshal(fields []WHOISField) (*Domain, string, error) {
+	domain := &Domain{
+		Class:  ClassDomain,
+		Events: make([]Event, 0),
+	}
+
+	var referral string
+
+	for _, field := range fields {
+		before, after := field.Key, field.Value
 
 		switch before {
 		case "Domain Name":
 			domain.Name = after
 			link, err := urlpkg.JoinPath(whoisBaseURL, "whois", after)
 			if err != nil {
-				return nil, err
+				return nil, "", err
 			}
 			domain.Link = link
 		case "Creation Date":
-			if err := c.unmarshalEvent(after, domain, "registration"); err != nil {
-				return nil, err
-			}
+			c.unmarshalEvent(after, domain, ActionRegistration)
+		case "Registrar Registration Expiration Date":
+			c.unmarshalEvent(after, domain, ActionRegistrarExpiration)
+		case "Registrar WHOIS Server":
+			referral = after
 		case "Registry Expiry Date":
-			if err := c.unmarshalEvent(after, domain, "expiration"); err != nil {
-				return nil, err
-			}
+			c.unmarshalEvent(after, domain, ActionExpiration)
 		case "Updated Date":
-			if err := c.unmarshalEvent(after, domain, "last changed"); err != nil {
-				return nil, err
+			c.unmarshalEvent(after, domain, ActionLastChanged)
+		case "Domain Status":
+			if status, _, _ := strings.Cut(after, " "); status != "" {
+				domain.Status = append(domain.Status, eppStatus(status))
 			}
-		}
-
-		if strings.HasPrefix(before, ">>>") {
-			data := updateRE.FindString(after)
-			action := "last update of WHOIS database"
-			if err := c.unmarshalEvent(data, domain, action); err != nil {
-				return nil, err
+		case "Registrar":
+			domain.whoisRegistrar().Name = after
+		case "Registrar IANA ID":
+			domain.whoisRegistrar().IANAID = after
+		case "Registrar Abuse Contact Email":
+			if after != "" {
+				domain.whoisContact("abuse").Email = after
+			}
+		case "Registrar Abuse Contact Phone":
+			if after != "" {
+				domain.whoisContact("abuse").Phone = after
+			}
+		case "DNSSEC":
+			switch strings.ToLower(after) {
+			case "signeddelegation", "signed", "yes":
+				domain.whoisDNSSEC().Signed = true
+			case "unsigned", "unsigneddelegation", "no":
+				domain.whoisDNSSEC().Signed = false
+			}
+		case "DNSSEC DS Data":
+			ds, err := c.unmarshalDS(after)
+			if err != nil {
+				return nil, "", err
+			}
+			dnssec := domain.whoisDNSSEC()
+			dnssec.DS = append(dnssec.DS, ds)
+		case "Name Server":
+			if after != "" {
+				domain.Nameservers = append(domain.Nameservers, after)
 			}
-			break
+		case "Last Update of WHOIS Database":
+			c.unmarshalEvent(after, domain, ActionLastWHOISUpdate)
+		default:
+			c.unmarshalContact(before, after, domain)
 		}
 	}
 
-	if len(domain.Events) == 0 {
-		return nil, nil
+	// Responses without a domain name, such as a not found message
+	// followed by the database update stamp, hold no registration.
+	if domain.Name == "" || len(domain.Events) == 0 {
+		return nil, "", nil
 	}
 
-	return domain, nil
+	return domain, referral, nil
 }
 
-func (c *WHOISClient) unmarshalEvent(data string, domain *Domain, action string) error {
-	t, err := time.Parse(time.RFC3339, data)
+// unmarshalEvent adds an event dated data to domain. Dates that cannot
+// be parsed are skipped rather than failing the whole response.
+func (c *WHOISClient) unmarshalEvent(data string, domain *Domain, action EventAction) {
+	if data == "" {
+		return
+	}
+	t, err := parseWHOISDate(data)
 	if err != nil {
-		return err
+		return
 	}
 	domain.Events = append(domain.Events, Event{
 		Action: action,
 		Date:   t,
 	})
-	return nil
 }
 
-func (c *WHOISClient) cutTrim(line string) (string, string, bool) {
-	b, a, ok := strings.Cut(line, ":")
-	b = strings.TrimSpace(b)
-	a = strings.TrimSpace(a)
-	return b, a, ok
+func (c *WHOISClient) unmarshalDS(data string) (DSRecord, error) {
+	fields := strings.Fields(data)
+	if len(fields) != 4 {
+		return DSRecord{}, fmt.Errorf("malformed DS data %q", data)
+	}
+
+	var ds DSRecord
+	var err error
+	for i, p := range []*int{&ds.KeyTag, &ds.Algorithm, &ds.DigestType} {
+		*p, err = strconv.Atoi(fields[i])
+		if err != nil {
+			return DSRecord{}, fmt.Errorf("malformed DS data %q: %w", data, err)
+		}
+	}
+	ds.Digest = fields[3]
+
+	return ds, nil
+}
+
+func (d *Domain) whoisDNSSEC() *DNSSEC {
+	if d.DNSSEC == nil {
+		d.DNSSEC = &DNSSEC{}
+	}
+	return d.DNSSEC
+}
+
+func (d *Domain) whoisRegistrar() *Entity {
+	if d.Registrar == nil {
+		d.Registrar = &Entity{Roles: []string{"registrar"}}
+	}
+	return d.Registrar
+}
+
+// unmarshalContact parses contact lines such as "Registrant Email" and
+// records placeholder values as redacted.
+func (c *WHOISClient) unmarshalContact(key, value string, domain *Domain) {
+	prefix, field, _ := strings.Cut(key, " ")
+
+	var role string
+	for r, p := range contactRoles {
+		if p == prefix {
+			role = r
+		}
+	}
+	if role == "" || value == "" {
+		return
+	}
+
+	switch field {
+	case "Name", "Organization", "Email", "Phone":
+	default:
+		return
+	}
+
+	if isRedactionPlaceholder(value) {
+		domain.redact(Redaction{
+			Name:   key,
+			Method: RedactionEmptyValue,
+			Reason: value,
+		})
+		return
+	}
+
+	contact := domain.whoisContact(role)
+	switch field {
+	case "Name":
+		contact.Name = value
+	case "Organization":
+		contact.Org = value
+	case "Email":
+		contact.Email = value
+	case "Phone":
+		contact.Phone = value
+	}
+}
+
+func (d *Domain) hasContact(role string) bool {
+	for i := range d.Contacts {
+		if d.Contacts[i].HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Domain) whoisContact(role string) *Entity {
+	for i := range d.Contacts {
+		if d.Contacts[i].HasRole(role) {
+			return &d.Contacts[i]
+		}
+	}
+	d.Contacts = append(d.Contacts, Entity{Roles: []string{role}})
+	return &d.Contacts[len(d.Contacts)-1]
+}
+
+// eppStatus maps an EPP status code to its RDAP status value (RFC 8056).
+func eppStatus(code string) string {
+	switch code {
+	case "ok":
+		return "active"
+	case "linked":
+		return "associated"
+	}
+	if strings.ToUpper(code) == code {
+		return strings.ToLower(code)
+	}
+
+	var b strings.Builder
+	for i, r := range code {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
 }
@@ -3,6 +3,7 @@ package indeed
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -45,8 +46,7 @@ func whoisServer(ch chan<- net.Addr) error {
 	case "example.org":
 		name = "testdata/whois-example-org.txt"
 	default:
-		_, err := fmt.Fprint(conn, "Domain not found.\r\n")
-		return err
+		name = "testdata/whois-not-found-com.txt"
 	}
 
 	f, err := os.Open(name)
@@ -62,18 +62,76 @@ func whoisServer(ch chan<- net.Addr) error {
 	return nil
 }
 
+func whoisFileServer(ch chan<- net.Addr, name string) error {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	return whoisTextServer(ch, string(b))
+}
+
+func whoisTextServer(ch chan<- net.Addr, text string) error {
+	l, err := net.Listen("tcp", ":")
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+
+	ch <- l.Addr()
+
+	conn, err := l.Accept()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := textproto.NewReader(bufio.NewReader(conn)).ReadLine(); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(conn, text)
+	return err
+}
+
 func TestWHOIS(t *testing.T) {
 	tests := []struct {
 		description string
 		name        string
 		want        *Domain
+		err         error
 	}{
 		{
 			"ok",
 			"example.com",
 			&Domain{
-				Name: "EXAMPLE.COM",
-				Link: "https://www.whois.com/whois/EXAMPLE.COM",
+				Class: ClassDomain,
+				Name:  "EXAMPLE.COM",
+				Link:  "https://www.whois.com/whois/EXAMPLE.COM",
+				Status: []string{
+					"client delete prohibited",
+					"client transfer prohibited",
+					"client update prohibited",
+				},
+				Nameservers: []string{
+					"A.IANA-SERVERS.NET",
+					"B.IANA-SERVERS.NET",
+				},
+				Registrar: &Entity{
+					Roles:  []string{"registrar"},
+					Name:   "RESERVED-Internet Assigned Numbers Authority",
+					IANAID: "376",
+				},
+				DNSSEC: &DNSSEC{
+					Signed: true,
+					DS: []DSRecord{
+						{
+							KeyTag:     370,
+							Algorithm:  13,
+							DigestType: 2,
+							Digest:     "BE74359954660069D5C63D200C39F5603827D7DD02B56F120EE9F3A86764247C",
+						},
+					},
+				},
 				Events: []Event{
 					{
 						Action: "last changed",
@@ -88,16 +146,18 @@ func TestWHOIS(t *testing.T) {
 						Date:   time.Date(2024, 8, 13, 4, 0, 0, 0, time.UTC),
 					},
 					{
-						Action: "last update of WHOIS database",
+						Action: ActionLastWHOISUpdate,
 						Date:   time.Date(2023, 9, 6, 11, 4, 43, 0, time.UTC),
 					},
 				},
 			},
+			nil,
 		},
 		{
 			"not found",
 			"404.com",
 			nil,
+			ErrWHOISNotFound,
 		},
 	}
 	for _, tc := range tests {
@@ -114,13 +174,11 @@ func TestWHOIS(t *testing.T) {
 				addr := <-ch
 
 				c := &WHOISClient{
-					m: func(string) string {
-						return addr.String()
-					},
+					TLDs: map[string]WHOISServer{"com": {Addr: addr.String()}},
 				}
 				got, err := c.Resolve(ctx, tc.name)
-				if err != nil {
-					return err
+				if !errors.Is(err, tc.err) {
+					return fmt.Errorf("got: %v; want: %v", err, tc.err)
 				}
 
 				if !reflect.DeepEqual(got, tc.want) {
@@ -136,3 +194,157 @@ func TestWHOIS(t *testing.T) {
 		})
 	}
 }
+
+func TestWHOISUnmarshal(t *testing.T) {
+	expiration := Event{Action: ActionExpiration, Date: time.Date(2024, 8, 13, 4, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		description string
+		fields      []WHOISField
+		want        []Event
+	}{
+		{
+			"bad date",
+			[]WHOISField{
+				{"Domain Name", "EXAMPLE.COM"},
+				{"Creation Date", "sometime in 1995"},
+				{"Registry Expiry Date", "2024-08-13T04:00:00Z"},
+			},
+			[]Event{expiration},
+		},
+		{
+			"update stamp only",
+			[]WHOISField{
+				{"Last Update of WHOIS Database", "2023-09-06T11:04:43Z"},
+			},
+			nil,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			domain, _, err := (&WHOISClient{}).unmarshal(tc.fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []Event
+			if domain != nil {
+				got = domain.Events
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got: %v; want: %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWHOISReferral(t *testing.T) {
+	registrar := make(chan net.Addr)
+	registry := make(chan net.Addr)
+
+	var g errgroup.Group
+	g.Go(func() error {
+		return whoisFileServer(registrar, "testdata/whois-registrar-example-com.txt")
+	})
+
+	source := (<-registrar).String()
+	g.Go(func() error {
+		return whoisTextServer(registry, "Domain Name: EXAMPLE.COM\r\n"+
+			"Registrar WHOIS Server: "+source+"\r\n"+
+			"Creation Date: 1995-08-14T04:00:00Z\r\n"+
+			"Registry Expiry Date: 2024-08-13T04:00:00Z\r\n"+
+			"Registrar: Example Registrar, Inc.\r\n")
+	})
+
+	addr := <-registry
+	c := &WHOISClient{
+		Referrals: 1,
+		TLDs:      map[string]WHOISServer{"com": {Addr: addr.String()}},
+	}
+	got, err := c.Resolve(context.Background(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	var events []EventAction
+	for _, event := range got.Events {
+		events = append(events, event.Action)
+	}
+	want := []EventAction{ActionRegistration, ActionExpiration, ActionLastChanged, ActionRegistrarExpiration, ActionLastWHOISUpdate}
+	if !reflect.DeepEqual(events, want) {
+		t.Fatalf("got: %v; want: %v", events, want)
+	}
+	if got.Events[0].Source != "" || got.Events[3].Source != source {
+		t.Fatalf("got: %v; want: registrar events from %s", got.Events, source)
+	}
+
+	contacts := []Entity{
+		{Roles: []string{"abuse"}, Email: "abuse@registrar.example", Phone: "+1.5555551234"},
+		{Roles: []string{"registrant"}, Org: "Internet Assigned Numbers Authority"},
+	}
+	if !reflect.DeepEqual(got.Contacts, contacts) {
+		t.Fatalf("got: %v; want: %v", got.Contacts, contacts)
+	}
+	if !got.IsRedacted("Registrant Email") {
+		t.Fatalf("got: %v; want: Registrant Email redacted", got.Redacted)
+	}
+}
+
+func TestWHOISReferralUnreachable(t *testing.T) {
+	l, err := net.Listen("tcp", ":")
+	if err != nil {
+		t.Fatal(err)
+	}
+	registrar := l.Addr().String()
+	l.Close()
+
+	registry := make(chan net.Addr)
+	var g errgroup.Group
+	g.Go(func() error {
+		return whoisTextServer(registry, "Domain Name: EXAMPLE.COM\r\n"+
+			"Registrar WHOIS Server: "+registrar+"\r\n"+
+			"Creation Date: 1995-08-14T04:00:00Z\r\n")
+	})
+
+	c := &WHOISClient{
+		Referrals: 1,
+		TLDs:      map[string]WHOISServer{"com": {Addr: (<-registry).String()}},
+	}
+	got, err := c.Resolve(context.Background(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Event{{Action: ActionRegistration, Date: time.Date(1995, 8, 14, 4, 0, 0, 0, time.UTC)}}
+	if !reflect.DeepEqual(got.Events, want) {
+		t.Fatalf("got: %v; want: %v", got.Events, want)
+	}
+}
+
+func TestEPPStatus(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"ok", "active"},
+		{"clientHold", "client hold"},
+		{"pendingDelete", "pending delete"},
+		{"serverTransferProhibited", "server transfer prohibited"},
+		{"autoRenewPeriod", "auto renew period"},
+		{"ACTIVE", "active"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.code, func(t *testing.T) {
+			if got := eppStatus(tc.code); got != tc.want {
+				t.Fatalf("got: %q; want: %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package indeed
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// whoisCharsets maps WHOIS server hosts to the charset their responses
+// have when they are not UTF-8, which is assumed when they are valid
+// UTF-8.
+var whoisCharsets = map[string]string{
+	"whois.registro.br": "iso-8859-1",
+}
+
+// decode converts a response to UTF-8 from label, the charset configured
+// for the server or, failing that, the built-in or detected one.
+func (c *WHOISClient) decode(addr, label string, text []byte) ([]byte, error) {
+	host := whoisHost(addr)
+	ok := label != ""
+	if !ok {
+		label, ok = c.Charsets[host]
+	}
+	if !ok && !utf8.Valid(text) {
+		label, ok = whoisCharsets[host]
+	}
+	if !ok || label == "" {
+		label = detectCharset(text)
+	}
+	if label == "" {
+		return text, nil
+	}
+
+	enc, err := htmlindex.Get(label)
+	if err != nil {
+		return nil, fmt.Errorf("WHOIS %s: charset %q: %w", addr, label, err)
+	}
+	return enc.NewDecoder().Bytes(text)
+}
+
+// detectCharset guesses the charset of text. It returns "" for UTF-8,
+// which includes plain ASCII.
+func detectCharset(text []byte) string {
+	switch {
+	case bytes.Contains(text, []byte("\x1b$B")), bytes.Contains(text, []byte("\x1b$@")):
+		return "iso-2022-jp"
+	case utf8.Valid(text):
+		return ""
+	case isEUCJP(text):
+		return "euc-jp"
+	default:
+		return "windows-1252"
+	}
+}
+
+// isEUCJP reports whether every non-ASCII byte of text is part of a
+// well-formed EUC-JP sequence. Latin-1 text rarely is, since accented
+// letters are mostly followed by ASCII.
+func isEUCJP(text []byte) bool {
+	kanji := func(b byte) bool {
+		return b >= 0xa1 && b <= 0xfe
+	}
+
+	for i := 0; i < len(text); i++ {
+		b := text[i]
+		switch {
+		case b < 0x80:
+		case b == 0x8e && i+1 < len(text) && text[i+1] >= 0xa1 && text[i+1] <= 0xdf:
+			i++
+		case b == 0x8f && i+2 < len(text) && kanji(text[i+1]) && kanji(text[i+2]):
+			i += 2
+		case kanji(b) && i+1 < len(text) && kanji(text[i+1]):
+			i++
+		default:
+			return false
+		}
+	}
+	return true
+}
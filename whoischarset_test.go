@@ -0,0 +1,84 @@
+package indeed
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/text/encoding/japanese"
+)
+
+func TestWHOISDecode(t *testing.T) {
+	eucJP, err := japanese.EUCJP.NewEncoder().String("株式会社日本レジストリサービス")
+	if err != nil {
+		t.Fatal(err)
+	}
+	iso2022JP, err := japanese.ISO2022JP.NewEncoder().String("株式会社日本レジストリサービス")
+	if err != nil {
+		t.Fatal(err)
+	}
+	shiftJIS, err := japanese.ShiftJIS.NewEncoder().String("株式会社日本レジストリサービス")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		description string
+		addr        string
+		text        string
+		want        string
+	}{
+		{"utf-8", "whois.example:43", "Registrar: Société Exemple", "Registrar: Société Exemple"},
+		{"latin-1", "whois.example:43", "Registrar: Soci\xe9t\xe9 Exemple", "Registrar: Société Exemple"},
+		{"euc-jp", "whois.example:43", eucJP, "株式会社日本レジストリサービス"},
+		{"iso-2022-jp", "whois.example:43", iso2022JP, "株式会社日本レジストリサービス"},
+		{"configured", "whois.sjis.example:43", shiftJIS, "株式会社日本レジストリサービス"},
+		{"built-in", "whois.registro.br:43", "owner: Jo\xe3o", "owner: João"},
+		{"built-in utf-8", "whois.registro.br:43", "owner: João", "owner: João"},
+		{"jprs iso-2022-jp", "whois.jprs.jp:43", iso2022JP, "株式会社日本レジストリサービス"},
+		{"jprs euc-jp", "whois.jprs.jp:43", eucJP, "株式会社日本レジストリサービス"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			c := &WHOISClient{
+				Charsets: map[string]string{"whois.sjis.example": "shift_jis"},
+			}
+			got, err := c.decode(tc.addr, "", []byte(tc.text))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("got: %q; want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWHOISCharset(t *testing.T) {
+	ch := make(chan net.Addr)
+
+	var g errgroup.Group
+	g.Go(func() error {
+		return whoisTextServer(ch, "Domain Name: EXAMPLE.COM\r\n"+
+			"Creation Date: 1995-08-14T04:00:00Z\r\n"+
+			"Registrar: Soci\xe9t\xe9 Exemple\r\n")
+	})
+
+	addr := <-ch
+	c := &WHOISClient{
+		TLDs: map[string]WHOISServer{"com": {Addr: addr.String()}},
+	}
+	domain, err := c.Resolve(context.Background(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "Société Exemple"; domain.Registrar == nil || domain.Registrar.Name != want {
+		t.Fatalf("got: %v; want: %q", domain.Registrar, want)
+	}
+}
@@ -0,0 +1,124 @@
+package indeed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// NoWHOISServer is the WHOISServer address of a top-level domain without
+// a WHOIS server.
+const NoWHOISServer = "-"
+
+var (
+	errBadQuery = errors.New("WHOIS query template without {name}")
+	errBadTLD   = errors.New("not a top-level domain")
+)
+
+// WHOISServer configures the WHOIS server of a top-level domain. An empty
+// Addr means the server is asked of the IANA server, and NoWHOISServer
+// that the domain has none. Query is a template with a {name}
+// placeholder, Profile a name accepted by ParseWHOISProfile and Charset a
+// label such as "iso-8859-1". They apply to that domain's lookups only,
+// not to referrals or other domains on the same server.
+type WHOISServer struct {
+	Addr    string `json:"server"`
+	Query   string `json:"query"`
+	Profile string `json:"profile"`
+	Charset string `json:"charset"`
+}
+
+// ParseWHOISConfig parses a JSON object mapping top-level domains to
+// their WHOIS server.
+func ParseWHOISConfig(r io.Reader) (map[string]WHOISServer, error) {
+	var body map[string]WHOISServer
+	if err := json.NewDecoder(r).Decode(&body); err != nil {
+		return nil, err
+	}
+	return canonicalWHOISServers(body)
+}
+
+// canonicalWHOISServers validates servers and returns them keyed by
+// canonical top-level domain.
+func canonicalWHOISServers(servers map[string]WHOISServer) (map[string]WHOISServer, error) {
+	tlds := make(map[string]WHOISServer, len(servers))
+	for key, server := range servers {
+		tld, err := canonicalTLD(key)
+		if err != nil {
+			return nil, fmt.Errorf("WHOIS server of %q: %w", key, errBadTLD)
+		}
+		if _, ok := tlds[tld]; ok {
+			return nil, fmt.Errorf("WHOIS server of %q: duplicate top-level domain", key)
+		}
+		if err := server.validate(); err != nil {
+			return nil, fmt.Errorf("WHOIS server of %q: %w", key, err)
+		}
+		tlds[tld] = server
+	}
+	return tlds, nil
+}
+
+func (s WHOISServer) validate() error {
+	if s.Query != "" && !strings.Contains(s.Query, "{name}") {
+		return errBadQuery
+	}
+	if s.Profile != "" {
+		if _, err := ParseWHOISProfile(s.Profile); err != nil {
+			return err
+		}
+	}
+	if s.Charset != "" {
+		if _, err := htmlindex.Get(s.Charset); err != nil {
+			return fmt.Errorf("charset %q: %w", s.Charset, err)
+		}
+	}
+	return nil
+}
+
+// LoadConfig reads a WHOIS config file and adds its servers to TLDs.
+func (c *WHOISClient) LoadConfig(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tlds, err := ParseWHOISConfig(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	c.addTLDs(tlds)
+	return nil
+}
+
+// Configure adds servers to TLDs.
+func (c *WHOISClient) Configure(servers map[string]WHOISServer) error {
+	tlds, err := canonicalWHOISServers(servers)
+	if err != nil {
+		return err
+	}
+	c.addTLDs(tlds)
+	return nil
+}
+
+func (c *WHOISClient) addTLDs(tlds map[string]WHOISServer) {
+	if c.TLDs == nil {
+		c.TLDs = make(map[string]WHOISServer)
+	}
+	for tld, server := range tlds {
+		c.TLDs[tld] = server
+	}
+}
+
+// query returns the query sent for name.
+func (s WHOISServer) query(name string) string {
+	if s.Query == "" {
+		return name
+	}
+	return strings.ReplaceAll(s.Query, "{name}", name)
+}
@@ -0,0 +1,153 @@
+package indeed
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/textproto"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/sync/errgroup"
+)
+
+func TestWHOISConfig(t *testing.T) {
+	c := NewWHOISClient()
+	if err := c.LoadConfig("testdata/whois-config.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]WHOISServer{
+		"de":   {Addr: "whois.denic.de", Query: "-T dn,ace {name}", Profile: "de"},
+		"jp":   {Addr: "whois.jprs.jp:43", Query: "{name}/e", Profile: "jp", Charset: "iso-2022-jp"},
+		"net":  {Addr: "whois.verisign-grs.com", Query: "domain {name}"},
+		"io":   {Profile: "icann"},
+		"test": {Addr: NoWHOISServer},
+	}
+	if !reflect.DeepEqual(c.TLDs, want) {
+		t.Fatalf("got: %v; want: %v", c.TLDs, want)
+	}
+	if c.Profiles != nil || c.Charsets != nil {
+		t.Fatalf("got: %v, %v; want: nil", c.Profiles, c.Charsets)
+	}
+}
+
+func TestParseWHOISConfig(t *testing.T) {
+	tests := []struct {
+		description string
+		config      string
+		err         error
+	}{
+		{"valid", `{"io": {"server": "whois.nic.io", "query": "{name}", "profile": "icann", "charset": "utf-8"}}`, nil},
+		{"query without name", `{"de": {"server": "whois.denic.de", "query": "-T dn,ace"}}`, errBadQuery},
+		{"unknown profile", `{"de": {"server": "whois.denic.de", "profile": "denic"}}`, errBadProfile},
+		{"multi-label", `{"co.uk": {"server": "whois.nic.uk"}}`, errBadTLD},
+		{"empty", `{"": {"server": "whois.nic.io"}}`, errBadTLD},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := ParseWHOISConfig(strings.NewReader(tc.config))
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("got: %v; want: %v", err, tc.err)
+			}
+		})
+	}
+
+	if _, err := ParseWHOISConfig(strings.NewReader(`{"br": {"charset": "latin-42"}}`)); err == nil {
+		t.Fatal("got: nil; want: error")
+	}
+
+	c := NewWHOISClient()
+	if err := c.Configure(map[string]WHOISServer{"de": {}, ".DE": {}}); err == nil {
+		t.Fatal("got: nil; want: error")
+	}
+}
+
+func TestWHOISServerQuery(t *testing.T) {
+	tests := []struct {
+		description string
+		query       string
+		want        string
+	}{
+		{"bare", "", "example.io"},
+		{"template", "domain {name}", "domain example.io"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			s := WHOISServer{Query: tc.query}
+			if got := s.query("example.io"); got != tc.want {
+				t.Fatalf("got: %q; want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWHOISQuery(t *testing.T) {
+	ch := make(chan net.Addr)
+	queries := make(chan string, 1)
+
+	var g errgroup.Group
+	g.Go(func() error {
+		l, err := net.Listen("tcp", ":")
+		if err != nil {
+			return err
+		}
+		defer l.Close()
+
+		ch <- l.Addr()
+
+		for i := 0; i < 2; i++ {
+			if err := func() error {
+				conn, err := l.Accept()
+				if err != nil {
+					return err
+				}
+				defer conn.Close()
+
+				line, err := textproto.NewReader(bufio.NewReader(conn)).ReadLine()
+				if err != nil {
+					return err
+				}
+				queries <- line
+
+				_, err = fmt.Fprintf(conn, "Domain Name: %s\r\nCreation Date: 2020-01-02T03:04:05Z\r\n", strings.TrimPrefix(line, "="))
+				return err
+			}(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	// Both top-level domains share the server, but only com is
+	// configured with a query template.
+	addr := (<-ch).String()
+	c := &WHOISClient{
+		TLDs: map[string]WHOISServer{
+			"com": {Addr: addr, Query: "={name}"},
+			"org": {Addr: addr},
+		},
+	}
+	for _, tc := range []struct {
+		name string
+		want string
+	}{
+		{"example.com", "=example.com"},
+		{"example.org", "example.org"},
+	} {
+		if _, err := c.Resolve(context.Background(), tc.name); err != nil {
+			t.Fatal(err)
+		}
+		if got := <-queries; got != tc.want {
+			t.Fatalf("got: %q; want: %q", got, tc.want)
+		}
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+}
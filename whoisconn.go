@@ -0,0 +1,89 @@
+package indeed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+)
+
+var ErrWHOISTimeout = errors.New("timeout")
+
+// exchange sends a query to a WHOIS server and reads the response.
+// Dialing and reading stop at the earliest of the context
+// deadline, the client timeouts and the cancellation of ctx.
+func (c *WHOISClient) exchange(ctx context.Context, addr, query string) ([]byte, error) {
+	dialer := net.Dialer{Timeout: c.DialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, c.error(ctx, addr, err)
+	}
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if c.ReadTimeout > 0 {
+		if d := time.Now().Add(c.ReadTimeout); !ok || d.Before(deadline) {
+			deadline, ok = d, true
+		}
+	}
+	if ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	if _, err := fmt.Fprintf(conn, "%s\r\n", query); err != nil {
+		return nil, c.error(ctx, addr, err)
+	}
+
+	text, err := io.ReadAll(io.LimitReader(conn, maxWHOISResponse))
+	if err != nil {
+		return nil, c.error(ctx, addr, err)
+	}
+	return text, nil
+}
+
+func (c *WHOISClient) error(ctx context.Context, addr string, err error) error {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return ctx.Err()
+	}
+
+	var netErr net.Error
+	if errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout() {
+		return &WHOISError{
+			Server: addr,
+			err:    ErrWHOISTimeout,
+			cause:  err,
+		}
+	}
+	return err
+}
+
+type WHOISError struct {
+	Server string
+	err    error
+	cause  error
+}
+
+func (e *WHOISError) Error() string {
+	msg := fmt.Sprintf("WHOIS %s: %v", e.Server, e.err)
+	if e.cause != nil {
+		msg = fmt.Sprintf("%s: %v", msg, e.cause)
+	}
+	return msg
+}
+
+func (e *WHOISError) Unwrap() []error {
+	if e.cause != nil {
+		return []error{e.err, e.cause}
+	}
+	return []error{e.err}
+}
@@ -0,0 +1,89 @@
+package indeed
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestWHOISTimeout(t *testing.T) {
+	l, err := net.Listen("tcp", ":")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	// Accept connections but never answer.
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	tests := []struct {
+		description string
+		readTimeout time.Duration
+		ctx         func() (context.Context, context.CancelFunc)
+		want        error
+	}{
+		{
+			"read timeout",
+			50 * time.Millisecond,
+			func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			ErrWHOISTimeout,
+		},
+		{
+			"context deadline",
+			0,
+			func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			ErrWHOISTimeout,
+		},
+		{
+			"context canceled",
+			0,
+			func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(50*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			context.Canceled,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			c := &WHOISClient{
+				ReadTimeout: tc.readTimeout,
+				TLDs:        map[string]WHOISServer{"com": {Addr: l.Addr().String()}},
+			}
+
+			ctx, cancel := tc.ctx()
+			defer cancel()
+
+			done := make(chan error, 1)
+			go func() {
+				_, err := c.Resolve(ctx, "example.com")
+				done <- err
+			}()
+
+			select {
+			case err := <-done:
+				if !errors.Is(err, tc.want) {
+					t.Fatalf("got: %v; want: %v", err, tc.want)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("got: no response; want: error")
+			}
+		})
+	}
+}
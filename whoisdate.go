@@ -0,0 +1,82 @@
+package indeed
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var whoisDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	"2006.01.02 15:04:05",
+	"2006.01.02",
+	"02-Jan-2006",
+	"02-January-2006",
+	"02.01.2006 15:04:05",
+	"02.01.2006",
+	"20060102",
+	"Mon Jan 2 15:04:05 2006",
+	"January 2 2006",
+	"2 January 2006",
+}
+
+// whoisZones maps time zone abbreviations used by WHOIS servers to their
+// offsets. Abbreviations are ambiguous in general, so only unambiguous
+// ones are known; CST and IST, for example, are not.
+var whoisZones = map[string]int{
+	"UTC":  0,
+	"GMT":  0,
+	"Z":    0,
+	"CET":  1 * 60 * 60,
+	"CEST": 2 * 60 * 60,
+	"EET":  2 * 60 * 60,
+	"EEST": 3 * 60 * 60,
+	"MSK":  3 * 60 * 60,
+	"HKT":  8 * 60 * 60,
+	"JST":  9 * 60 * 60,
+	"KST":  9 * 60 * 60,
+	"AEST": 10 * 60 * 60,
+	"BRT":  -3 * 60 * 60,
+	"EST":  -5 * 60 * 60,
+	"EDT":  -4 * 60 * 60,
+	"PST":  -8 * 60 * 60,
+	"PDT":  -7 * 60 * 60,
+}
+
+var whoisZoneRE = regexp.MustCompile(`\s*\(?\b([A-Z]{1,4})\)?$`)
+
+// parseWHOISDate parses the many date layouts found in WHOIS responses.
+// Dates without a zone are taken as UTC.
+func parseWHOISDate(s string) (time.Time, error) {
+	value := strings.TrimSpace(s)
+	// Drop trailing comments such as the ticket in "20230101 #1234".
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	value = strings.TrimSuffix(value, " UTC+0")
+	value = strings.Join(strings.Fields(value), " ")
+
+	loc := time.UTC
+	if m := whoisZoneRE.FindStringSubmatch(value); m != nil {
+		if offset, ok := whoisZones[m[1]]; ok {
+			loc = time.FixedZone(m[1], offset)
+			value = strings.TrimSpace(strings.TrimSuffix(value, m[0]))
+		}
+	}
+
+	for _, layout := range whoisDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("malformed date %q", s)
+}
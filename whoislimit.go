@@ -0,0 +1,184 @@
+package indeed
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strings"
+)
+
+var (
+	ErrWHOISNotFound    = errors.New("not found")
+	ErrWHOISRateLimited = errors.New("rate limited")
+)
+
+// WHOISMessages lists the lowercase beginnings of the free-text message
+// lines a WHOIS server answers with instead of registration data.
+type WHOISMessages struct {
+	NotFound    []string
+	RateLimited []string
+}
+
+var defaultWHOISMessages = WHOISMessages{
+	NotFound: []string{
+		"no match",
+		"not found",
+		"domain not found",
+		"no data found",
+		"no entries found",
+		"no matching record",
+		"no object found",
+		"object does not exist",
+		"the queried object does not exist",
+		"status: free",
+		"status: available",
+	},
+	RateLimited: []string{
+		"queried interval is too short",
+		"limit exceeded",
+		"query limit exceeded",
+		"rate limit exceeded",
+		"query rate limit exceeded",
+		"too many requests",
+		"quota exceeded",
+	},
+}
+
+// whoisMessages holds messages of particular servers, used in addition
+// to defaultWHOISMessages.
+var whoisMessages = map[string]WHOISMessages{
+	"whois.denic.de": {
+		RateLimited: []string{"55000000002"},
+	},
+	"whois.nic.uk": {
+		NotFound:    []string{"this domain name has not been registered"},
+		RateLimited: []string{"maximum query rate reached"},
+	},
+	"whois.jprs.jp": {
+		NotFound: []string{"no match!!"},
+	},
+	"whois.registro.br": {
+		RateLimited: []string{"query rate limit exceeded"},
+	},
+}
+
+// classify returns the error that a response without registration data
+// stands for, or nil when the response is not recognized. A message must
+// start a line, after any comment marker and "error:" prefix, so that
+// disclaimers merely mentioning it do not count.
+func (c *WHOISClient) classify(addr string, text []byte) error {
+	host := whoisHost(addr)
+	server := whoisMessages[host]
+	custom := c.Messages[host]
+
+	var line string
+	starts := func(messages ...[]string) bool {
+		for _, prefixes := range messages {
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(line, prefix) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	var notFound bool
+	s := bufio.NewScanner(bytes.NewReader(bytes.ToLower(text)))
+	for s.Scan() {
+		line = strings.TrimLeft(s.Text(), "%#*> \t")
+		line = strings.TrimSpace(strings.TrimPrefix(line, "error:"))
+
+		switch {
+		case starts(defaultWHOISMessages.RateLimited, server.RateLimited, custom.RateLimited):
+			return &WHOISError{Server: addr, err: ErrWHOISRateLimited}
+		case starts(defaultWHOISMessages.NotFound, server.NotFound, custom.NotFound):
+			notFound = true
+		}
+	}
+
+	if notFound {
+		return &WHOISError{Server: addr, err: ErrWHOISNotFound}
+	}
+	return nil
+}
+
+// query looks a name up, backing off and retrying when the server says
+// it is rate limited. conf is the zero WHOISServer for referrals.
+func (c *WHOISClient) query(ctx context.Context, addr, name string, conf WHOISServer) (*Domain, string, error) {
+	host := whoisHost(addr)
+	limit := c.limit(host)
+
+	for attempt := 0; ; attempt++ {
+		if err := c.buckets.get(host, limit).wait(ctx); err != nil {
+			return nil, "", err
+		}
+
+		domain, referral, err := c.queryOnce(ctx, addr, name, conf)
+		if !errors.Is(err, ErrWHOISRateLimited) || attempt >= limit.Retries {
+			return domain, referral, err
+		}
+
+		delay := limit.backoff(attempt)
+		if limit.MaxWait > 0 && delay > limit.MaxWait {
+			return nil, "", err
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, "", err
+		}
+	}
+}
+
+func (c *WHOISClient) queryOnce(ctx context.Context, addr, name string, conf WHOISServer) (*Domain, string, error) {
+	text, err := c.exchange(ctx, addr, conf.query(name))
+	if err != nil {
+		return nil, "", err
+	}
+
+	text, err = c.decode(addr, conf.Charset, text)
+	if err != nil {
+		return nil, "", err
+	}
+
+	profile := c.profile(addr)
+	if conf.Profile != "" {
+		if profile, err = ParseWHOISProfile(conf.Profile); err != nil {
+			return nil, "", err
+		}
+	}
+
+	fields, err := profile.Fields(bytes.NewReader(text))
+	if err != nil {
+		return nil, "", err
+	}
+
+	domain, referral, err := c.unmarshal(fields)
+	if err != nil {
+		return nil, "", err
+	}
+	if domain == nil {
+		return nil, "", c.classify(addr, text)
+	}
+	return domain, referral, nil
+}
+
+func (c *WHOISClient) limit(host string) RateLimit {
+	if limit, ok := c.Limits[host]; ok {
+		return limit
+	}
+	return c.DefaultLimit
+}
+
+func whoisTLD(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+func whoisHost(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	return strings.ToLower(host)
+}
@@ -0,0 +1,95 @@
+package indeed
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/textproto"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWHOISClassify(t *testing.T) {
+	tests := []struct {
+		description string
+		addr        string
+		text        string
+		want        error
+	}{
+		{"no match", "whois.verisign-grs.com:43", `No match for "404.COM".`, ErrWHOISNotFound},
+		{"free", "whois.denic.de:43", "Domain: 404.de\nStatus: free\n", ErrWHOISNotFound},
+		{"interval", "whois.nic.io:43", "Queried interval is too short.", ErrWHOISRateLimited},
+		{"limit exceeded", "whois.nic.io:43", "LIMIT EXCEEDED", ErrWHOISRateLimited},
+		{"server message", "whois.denic.de:43", "% Error: 55000000002 Connection refused; access control limit reached.", ErrWHOISRateLimited},
+		{"custom message", "whois.example:43", "Slow down, please.", ErrWHOISRateLimited},
+		{"not found with disclaimer", "whois.nic.io:43", "Domain not found.\n\nQueries over the rate limit are refused; try again later.\n", ErrWHOISNotFound},
+		{"disclaimer only", "whois.nic.io:43", "Terms of use: if the name is not found or the rate limit is exceeded, try again later.\n", nil},
+		{"unknown", "whois.example:43", "Something else.", nil},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			c := &WHOISClient{
+				Messages: map[string]WHOISMessages{
+					"whois.example": {RateLimited: []string{"slow down"}},
+				},
+			}
+			if got := c.classify(tc.addr, []byte(tc.text)); !errors.Is(got, tc.want) {
+				t.Fatalf("got: %v; want: %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWHOISRateLimited(t *testing.T) {
+	tests := []struct {
+		description string
+		retries     int
+		want        error
+	}{
+		{"retry", 1, nil},
+		{"no retries", 0, ErrWHOISRateLimited},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			l, err := net.Listen("tcp", ":")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer l.Close()
+
+			// Answer the first query with a rate limit message and the
+			// next with registration data.
+			go func() {
+				for i := 0; ; i++ {
+					conn, err := l.Accept()
+					if err != nil {
+						return
+					}
+					textproto.NewReader(bufio.NewReader(conn)).ReadLine()
+					if i == 0 {
+						conn.Write([]byte("LIMIT EXCEEDED\r\n"))
+					} else if b, err := os.ReadFile("testdata/whois-example-com.txt"); err == nil {
+						conn.Write(b)
+					}
+					conn.Close()
+				}
+			}()
+
+			c := &WHOISClient{
+				DefaultLimit: RateLimit{Retries: tc.retries, Backoff: 10 * time.Millisecond},
+				TLDs:         map[string]WHOISServer{"com": {Addr: l.Addr().String()}},
+			}
+			domain, err := c.Resolve(context.Background(), "example.com")
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("got: %v; want: %v", err, tc.want)
+			}
+			if err == nil && domain == nil {
+				t.Fatal("got: nil; want: domain")
+			}
+		})
+	}
+}
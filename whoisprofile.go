@@ -0,0 +1,247 @@
+package indeed
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// A WHOISProfile splits the response of a WHOIS server into fields keyed
+// like the ICANN registration data format, such as "Domain Name",
+// "Creation Date" and "Name Server".
+type WHOISProfile interface {
+	Fields(r io.Reader) ([]WHOISField, error)
+}
+
+type WHOISField struct {
+	Key   string
+	Value string
+}
+
+var errBadProfile = errors.New("unknown WHOIS profile")
+
+var (
+	WHOISProfileICANN WHOISProfile = icannProfile{}
+	WHOISProfileUK    WHOISProfile = ukProfile{}
+	WHOISProfileDE    WHOISProfile = keyValueProfile{
+		"domain":  "Domain Name",
+		"nserver": "Name Server",
+		"status":  "Domain Status",
+		"changed": "Updated Date",
+	}
+	WHOISProfileJP WHOISProfile = jpProfile{}
+	WHOISProfileFR WHOISProfile = keyValueProfile{
+		"domain":      "Domain Name",
+		"status":      "Domain Status",
+		"registrar":   "Registrar",
+		"expiry date": "Registry Expiry Date",
+		"created":     "Creation Date",
+		"last-update": "Updated Date",
+		"nserver":     "Name Server",
+	}
+	WHOISProfileBR WHOISProfile = keyValueProfile{
+		"domain":  "Domain Name",
+		"owner":   "Registrant Name",
+		"nserver": "Name Server",
+		"created": "Creation Date",
+		"changed": "Updated Date",
+		"expires": "Registry Expiry Date",
+		"status":  "Domain Status",
+	}
+)
+
+var whoisProfileNames = map[string]WHOISProfile{
+	"icann": WHOISProfileICANN,
+	"uk":    WHOISProfileUK,
+	"de":    WHOISProfileDE,
+	"jp":    WHOISProfileJP,
+	"fr":    WHOISProfileFR,
+	"br":    WHOISProfileBR,
+}
+
+// whoisProfiles maps WHOIS server hosts to their profile. Other servers
+// use WHOISProfileICANN.
+var whoisProfiles = map[string]WHOISProfile{
+	"whois.nic.uk":      WHOISProfileUK,
+	"whois.denic.de":    WHOISProfileDE,
+	"whois.jprs.jp":     WHOISProfileJP,
+	"whois.nic.fr":      WHOISProfileFR,
+	"whois.registro.br": WHOISProfileBR,
+}
+
+func ParseWHOISProfile(s string) (WHOISProfile, error) {
+	if profile, ok := whoisProfileNames[strings.ToLower(s)]; ok {
+		return profile, nil
+	}
+	return nil, errBadProfile
+}
+
+// whoisMultiValued lists the fields that may repeat. Profiles for formats
+// that repeat keys in contact blocks keep only the first of other fields.
+var whoisMultiValued = map[string]bool{
+	"Name Server":    true,
+	"Domain Status":  true,
+	"DNSSEC DS Data": true,
+}
+
+type icannProfile struct{}
+
+func (icannProfile) Fields(r io.Reader) ([]WHOISField, error) {
+	var fields []WHOISField
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		key, value, found := strings.Cut(s.Text(), ":")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		if strings.HasPrefix(key, ">>>") {
+			fields = append(fields, WHOISField{"Last Update of WHOIS Database", updateRE.FindString(value)})
+			break
+		}
+		fields = append(fields, WHOISField{key, value})
+	}
+	return fields, s.Err()
+}
+
+// keyValueProfile maps lowercase keys of "key: value" lines to fields.
+type keyValueProfile map[string]string
+
+func (p keyValueProfile) Fields(r io.Reader) ([]WHOISField, error) {
+	var fields []WHOISField
+	seen := make(map[string]bool)
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if strings.HasPrefix(line, "%") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+
+		field, ok := p[strings.ToLower(strings.TrimSpace(key))]
+		if !ok || seen[field] && !whoisMultiValued[field] {
+			continue
+		}
+		seen[field] = true
+		fields = append(fields, WHOISField{field, strings.TrimSpace(value)})
+	}
+	return fields, s.Err()
+}
+
+// ukProfile parses the Nominet format, where indented values follow
+// section headers such as "Name servers:".
+type ukProfile struct{}
+
+var (
+	ukSections = map[string]string{
+		"domain name": "Domain Name",
+		"registrant":  "Registrant Name",
+		"registrar":   "Registrar",
+		"dnssec":      "DNSSEC",
+	}
+	ukDates = map[string]string{
+		"registered on": "Creation Date",
+		"expiry date":   "Registry Expiry Date",
+		"last updated":  "Updated Date",
+	}
+	ukTagRE = regexp.MustCompile(`\s*\[Tag = [^\]]*\]$`)
+)
+
+func (ukProfile) Fields(r io.Reader) ([]WHOISField, error) {
+	var fields []WHOISField
+	var section string
+	var values int
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		if indent := len(line) - len(strings.TrimLeft(line, " \t")); indent < 8 {
+			key, value, _ := strings.Cut(trimmed, ":")
+			section = strings.ToLower(strings.TrimSpace(key))
+			values = 0
+			if value = strings.TrimSpace(value); value != "" {
+				if field, ok := ukSections[section]; ok {
+					fields = append(fields, WHOISField{field, value})
+				}
+			}
+			continue
+		}
+		values++
+
+		switch section {
+		case "relevant dates":
+			key, value, _ := strings.Cut(trimmed, ":")
+			if field, ok := ukDates[strings.ToLower(strings.TrimSpace(key))]; ok {
+				fields = append(fields, WHOISField{field, strings.TrimSpace(value)})
+			}
+		case "name servers":
+			if host := strings.Fields(trimmed)[0]; strings.Contains(host, ".") {
+				fields = append(fields, WHOISField{"Name Server", host})
+			}
+		default:
+			if field, ok := ukSections[section]; ok && values == 1 {
+				fields = append(fields, WHOISField{field, ukTagRE.ReplaceAllString(trimmed, "")})
+			}
+		}
+	}
+	return fields, s.Err()
+}
+
+// jpProfile parses the JPRS format of "[Key] value" lines, optionally
+// prefixed by a letter such as "a.".
+type jpProfile struct{}
+
+var (
+	jpKeys = map[string]string{
+		"domain name":     "Domain Name",
+		"name server":     "Name Server",
+		"registrant":      "Registrant Name",
+		"organization":    "Registrant Organization",
+		"created on":      "Creation Date",
+		"registered date": "Creation Date",
+		"expires on":      "Registry Expiry Date",
+		"last updated":    "Updated Date",
+		"last update":     "Updated Date",
+		"status":          "Domain Status",
+		"lock status":     "Domain Status",
+	}
+	jpLineRE  = regexp.MustCompile(`^(?:[a-z]\.\s*)?\[([^\]]+)\]\s*(.*)$`)
+	jpStateRE = regexp.MustCompile(`\((\d{4}/\d{2}/\d{2})\)`)
+)
+
+func (jpProfile) Fields(r io.Reader) ([]WHOISField, error) {
+	var fields []WHOISField
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		m := jpLineRE.FindStringSubmatch(strings.TrimSpace(s.Text()))
+		if m == nil {
+			continue
+		}
+		key, value := strings.ToLower(strings.TrimSpace(m[1])), strings.TrimSpace(m[2])
+
+		// "[State] Connected (2024/02/28)" holds the expiration date.
+		if key == "state" {
+			if date := jpStateRE.FindStringSubmatch(value); date != nil {
+				fields = append(fields, WHOISField{"Registry Expiry Date", date[1]})
+			}
+			continue
+		}
+
+		if field, ok := jpKeys[key]; ok {
+			fields = append(fields, WHOISField{field, value})
+		}
+	}
+	return fields, s.Err()
+}
@@ -0,0 +1,165 @@
+package indeed
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWHOISProfile(t *testing.T) {
+	tests := []struct {
+		profile     string
+		file        string
+		name        string
+		status      []string
+		nameservers []string
+		registrar   string
+		events      []Event
+	}{
+		{
+			"uk",
+			"testdata/whois-example-co-uk.txt",
+			"example.co.uk",
+			nil,
+			[]string{"ns1.example.co.uk", "ns2.example.co.uk"},
+			"Nominet UK",
+			[]Event{
+				{Action: ActionRegistration, Date: time.Date(1996, 8, 26, 0, 0, 0, 0, time.UTC)},
+				{Action: ActionExpiration, Date: time.Date(2025, 8, 26, 0, 0, 0, 0, time.UTC)},
+				{Action: ActionLastChanged, Date: time.Date(2023, 7, 26, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			"de",
+			"testdata/whois-example-de.txt",
+			"example.de",
+			[]string{"connect"},
+			[]string{"a.iana-servers.net", "b.iana-servers.net"},
+			"",
+			[]Event{
+				{Action: ActionLastChanged, Date: time.Date(2018, 3, 12, 20, 44, 25, 0, time.UTC)},
+			},
+		},
+		{
+			"jp",
+			"testdata/whois-example-jp.txt",
+			"EXAMPLE.JP",
+			[]string{"agent change locked"},
+			[]string{"ns1.example.jp", "ns2.example.jp"},
+			"",
+			[]Event{
+				{Action: ActionExpiration, Date: time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC)},
+				{Action: ActionRegistration, Date: time.Date(2001, 2, 2, 0, 0, 0, 0, time.UTC)},
+				{Action: ActionLastChanged, Date: time.Date(2023, 2, 28, 16, 5, 10, 0, time.UTC)},
+			},
+		},
+		{
+			"fr",
+			"testdata/whois-example-fr.txt",
+			"example.fr",
+			[]string{"active"},
+			[]string{"ns1.nic.fr", "ns2.nic.fr"},
+			"AFNIC",
+			[]Event{
+				{Action: ActionExpiration, Date: time.Date(2024, 12, 31, 23, 0, 0, 0, time.UTC)},
+				{Action: ActionRegistration, Date: time.Date(2005, 6, 28, 22, 0, 0, 0, time.UTC)},
+				{Action: ActionLastChanged, Date: time.Date(2023, 1, 10, 9, 9, 39, 631628000, time.UTC)},
+			},
+		},
+		{
+			"br",
+			"testdata/whois-example-com-br.txt",
+			"example.com.br",
+			[]string{"published"},
+			[]string{"a.dns.br", "b.dns.br"},
+			"",
+			[]Event{
+				{Action: ActionRegistration, Date: time.Date(1999, 5, 3, 0, 0, 0, 0, time.UTC)},
+				{Action: ActionLastChanged, Date: time.Date(2023, 4, 12, 0, 0, 0, 0, time.UTC)},
+				{Action: ActionExpiration, Date: time.Date(2025, 5, 3, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.profile, func(t *testing.T) {
+			profile, err := ParseWHOISProfile(tc.profile)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := os.Open(tc.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			fields, err := profile.Fields(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			domain, _, err := (&WHOISClient{}).unmarshal(fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if domain.Name != tc.name {
+				t.Fatalf("got: %v; want: %v", domain.Name, tc.name)
+			}
+			if !reflect.DeepEqual(domain.Status, tc.status) {
+				t.Fatalf("got: %v; want: %v", domain.Status, tc.status)
+			}
+			if !reflect.DeepEqual(domain.Nameservers, tc.nameservers) {
+				t.Fatalf("got: %v; want: %v", domain.Nameservers, tc.nameservers)
+			}
+			var registrar string
+			if domain.Registrar != nil {
+				registrar = domain.Registrar.Name
+			}
+			if registrar != tc.registrar {
+				t.Fatalf("got: %v; want: %v", registrar, tc.registrar)
+			}
+			if !reflect.DeepEqual(domain.Events, tc.events) {
+				t.Fatalf("got: %v; want: %v", domain.Events, tc.events)
+			}
+		})
+	}
+}
+
+func TestParseWHOISDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2023-08-14T07:01:38Z", time.Date(2023, 8, 14, 7, 1, 38, 0, time.UTC)},
+		{"2018-03-12T21:44:25+01:00", time.Date(2018, 3, 12, 20, 44, 25, 0, time.UTC)},
+		{"2023-08-14 07:01:38", time.Date(2023, 8, 14, 7, 1, 38, 0, time.UTC)},
+		{"2023-08-14 07:01:38 CEST", time.Date(2023, 8, 14, 5, 1, 38, 0, time.UTC)},
+		{"2023/03/01 01:05:10 (JST)", time.Date(2023, 2, 28, 16, 5, 10, 0, time.UTC)},
+		{"26-Aug-1996", time.Date(1996, 8, 26, 0, 0, 0, 0, time.UTC)},
+		{"19990503 #92178", time.Date(1999, 5, 3, 0, 0, 0, 0, time.UTC)},
+		{"12.03.2018", time.Date(2018, 3, 12, 0, 0, 0, 0, time.UTC)},
+		{"2018.03.12", time.Date(2018, 3, 12, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.value, func(t *testing.T) {
+			got, err := parseWHOISDate(tc.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tc.want) {
+				t.Fatalf("got: %v; want: %v", got, tc.want)
+			}
+		})
+	}
+
+	// Ambiguous dates and zones are rejected rather than guessed.
+	for _, value := range []string{"next tuesday", "03/04/2024", "2023-08-14 07:01:38 CST"} {
+		if _, err := parseWHOISDate(value); err == nil {
+			t.Fatalf("%s: got: nil; want: error", value)
+		}
+	}
+}
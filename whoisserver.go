@@ -0,0 +1,107 @@
+package indeed
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const IANAWHOISServer = "whois.iana.org:43"
+
+// server returns the WHOIS server address for name, asking IANAServer
+// once per top-level domain.
+func (c *WHOISClient) server(ctx context.Context, name string) (string, error) {
+	tld := whoisTLD(name)
+	switch addr := c.TLDs[tld].Addr; addr {
+	case NoWHOISServer:
+		return "", errNoServer
+	case "":
+	default:
+		return whoisAddr(addr), nil
+	}
+
+	if c.IANAServer == "" {
+		return "", errNoServer
+	}
+
+	c.mu.Lock()
+	addr, ok := c.servers[tld]
+	c.mu.Unlock()
+
+	if !ok {
+		ch := c.group.DoChan(tld, func() (any, error) {
+			// The lookup is shared, so a caller giving up must not
+			// fail it for the others.
+			addr, err := c.refer(context.WithoutCancel(ctx), tld)
+			if err != nil {
+				return "", err
+			}
+
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			if c.servers == nil {
+				c.servers = make(map[string]string)
+			}
+			c.servers[tld] = addr
+			return addr, nil
+		})
+
+		var result singleflight.Result
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case result = <-ch:
+		}
+		if result.Err != nil {
+			// An unreachable IANA server is not cached, so the
+			// top-level domain is retried on the next lookup.
+			return "", result.Err
+		}
+		addr = result.Val.(string)
+	}
+
+	if addr == "" {
+		return "", errNoServer
+	}
+	return addr, nil
+}
+
+// refer asks IANAServer for the WHOIS server of a top-level domain. An
+// empty address means IANA knows of no server.
+func (c *WHOISClient) refer(ctx context.Context, tld string) (string, error) {
+	text, err := c.exchange(ctx, c.IANAServer, tld)
+	if err != nil {
+		return "", err
+	}
+
+	fields, err := WHOISProfileICANN.Fields(bytes.NewReader(text))
+	if err != nil {
+		return "", err
+	}
+
+	var addr string
+	for _, field := range fields {
+		if field.Value == "" {
+			continue
+		}
+		switch strings.ToLower(field.Key) {
+		case "refer":
+			return whoisAddr(field.Value), nil
+		case "whois":
+			addr = whoisAddr(field.Value)
+		}
+	}
+
+	return addr, nil
+}
+
+// whoisAddr adds the WHOIS port to a host without one.
+func whoisAddr(host string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(host, "43")
+}
@@ -0,0 +1,129 @@
+package indeed
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/textproto"
+	"testing"
+	"time"
+)
+
+func ianaServer(ch chan<- net.Addr, referrals map[string]string) error {
+	l, err := net.Listen("tcp", ":")
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+
+	ch <- l.Addr()
+
+	conn, err := l.Accept()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	r := textproto.NewReader(bufio.NewReader(conn))
+	line, err := r.ReadLine()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(conn, "%% IANA WHOIS server\r\n\r\ndomain:       %s\r\n\r\n", line)
+	if referral, ok := referrals[line]; ok {
+		_, err := fmt.Fprintf(conn, "refer:        %s\r\n", referral)
+		return err
+	}
+	_, err = fmt.Fprint(conn, "status:       ACTIVE\r\n")
+	return err
+}
+
+func TestWHOISServerUnreachable(t *testing.T) {
+	l, err := net.Listen("tcp", ":")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	c := NewWHOISClient()
+	c.IANAServer = addr
+
+	if _, err := c.server(context.Background(), "example.io"); err == nil || errors.Is(err, errNoServer) {
+		t.Fatalf("got: %v; want: connection error", err)
+	}
+}
+
+func TestWHOISServerTimeout(t *testing.T) {
+	l, err := net.Listen("tcp", ":")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	c := NewWHOISClient()
+	c.IANAServer = l.Addr().String()
+	c.ReadTimeout = 10 * time.Millisecond
+
+	if _, err := c.server(context.Background(), "example.io"); !errors.Is(err, ErrWHOISTimeout) {
+		t.Fatalf("got: %v; want: %v", err, ErrWHOISTimeout)
+	}
+}
+
+func TestWHOISServer(t *testing.T) {
+	tests := []struct {
+		description string
+		name        string
+		tlds        map[string]WHOISServer
+		want        string
+		err         error
+	}{
+		{"referral", "example.io", nil, "whois.nic.io:43", nil},
+		{"port", "example.ch", nil, "whois.nic.ch:4343", nil},
+		{"no referral", "example.test", nil, "", errNoServer},
+		{"override", "example.io", map[string]WHOISServer{"io": {Addr: "whois.example"}}, "whois.example:43", nil},
+		{"override none", "example.io", map[string]WHOISServer{"io": {Addr: NoWHOISServer}}, "", errNoServer},
+		{"config without server", "example.io", map[string]WHOISServer{"io": {Query: "={name}"}}, "whois.nic.io:43", nil},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			c := NewWHOISClient()
+			c.TLDs = tc.tlds
+
+			discover := c.TLDs[whoisTLD(tc.name)].Addr == ""
+			errs := make(chan error, 1)
+			if discover {
+				ch := make(chan net.Addr)
+				go func() {
+					errs <- ianaServer(ch, map[string]string{
+						"io": "whois.nic.io",
+						"ch": "whois.nic.ch:4343",
+					})
+				}()
+				c.IANAServer = (<-ch).String()
+			}
+
+			// The IANA server answers once, so the second lookup must be
+			// served from the cache.
+			for i := 0; i < 2; i++ {
+				got, err := c.server(context.Background(), tc.name)
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("got: %v; want: %v", err, tc.err)
+				}
+				if got != tc.want {
+					t.Fatalf("got: %q; want: %q", got, tc.want)
+				}
+			}
+
+			if discover {
+				if err := <-errs; err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}